@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+// completeKeyPaths 解析--input或位置参数中指定的文件，补全其中的key路径
+func completeKeyPaths(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// 补全时不会执行PersistentPreRun，这里自己展开输入，有多个输入时使用第一个
+	_, files := splitInputArgs(args)
+	if err := resolveInputs(files...); err != nil || len(params.Inputs) == 0 || params.Inputs[0] == "-" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	data, err := pkg.DecodeTomlFile(params.Inputs[0], fetchOptions.MaxSize)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var paths []string
+	pkg.Walk(data, func(path []pkg.PathKey, value any) bool {
+		p := pkg.FormatPath(path)
+		if strings.HasPrefix(p, toComplete) {
+			paths = append(paths, p)
+		}
+		return true
+	})
+	return paths, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFirstKeyPath 只补全第一个参数，用于 set <path> <value>
+func completeFirstKeyPath(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if rest, _ := splitInputArgs(args); len(rest) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeKeyPaths(cmd, args, toComplete)
+}
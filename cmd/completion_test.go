@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestCompletedCommandRuns 补全得到的key路径放回命令行后命令应当能够执行成功
+func TestCompletedCommandRuns(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "c.toml", "[server]\nport = 80\nhost = \"x\"\n")
+
+	tests := []struct {
+		name     string
+		before   []string // 补全位置之前的参数
+		after    []string // 补全位置之后再补上的参数
+		complete string
+	}{
+		{"get file first", []string{"toml", "get", "c.toml"}, nil, "server.port"},
+		{"get with -i", []string{"toml", "get", "-i", "c.toml"}, nil, "server.port"},
+		{"set file first", []string{"toml", "set", "c.toml"}, []string{"81"}, "server.port"},
+		{"set with -i", []string{"toml", "set", "-i", "c.toml"}, []string{"81"}, "server.port"},
+		{"del file first", []string{"toml", "del", "c.toml"}, nil, "server.host"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completeArgs := append(append([]string{"__complete"}, tt.before...), "server.")
+			out, stderr, code := runAq(t, dir, "", completeArgs...)
+			if code != exitOK {
+				t.Fatalf("completion exited %d: %s", code, stderr)
+			}
+			candidates := strings.Split(strings.TrimSpace(out), "\n")
+			if !slices.Contains(candidates, tt.complete) {
+				t.Fatalf("completion %v does not offer %s", candidates, tt.complete)
+			}
+
+			args := append(append(append([]string(nil), tt.before...), tt.complete), tt.after...)
+			if _, stderr, code := runAq(t, dir, "", args...); code != exitOK {
+				t.Fatalf("aq %s exited %d: %s", strings.Join(args, " "), code, stderr)
+			}
+		})
+	}
+}
+
+// TestSetCompletesOnlyThePath set只补全路径，路径之后不再补全
+func TestSetCompletesOnlyThePath(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "c.toml", "port = 80\n")
+	out, _, _ := runAq(t, dir, "", "__complete", "toml", "set", "c.toml", "port", "")
+	if strings.Contains(out, "port") {
+		t.Fatalf("set completed the value position: %q", out)
+	}
+}
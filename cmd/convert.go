@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type ConvertParams struct {
+	From     string `json:"from"`     // 输入格式
+	To       string `json:"to"`       // 输出格式
+	Input    string `json:"input"`    // 输入文件路径
+	Output   string `json:"output"`   // 输出文件地址
+	Compact  bool   `json:"compact"`  // json输出为单行
+	Datetime string `json:"datetime"` // 时间的输出方式
+	Query    string `json:"query"`    // 转换前先执行的jq风格查询
+}
+
+var convertParams = &ConvertParams{}
+
+var convertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "convert between toml, json and yaml",
+	Long:  "Convert a document between toml, json and yaml. Formats are detected from the file extensions when --from/--to are not given; the output defaults to json. With --query only the query results are converted, one per line for json.",
+	Args:  cobra.NoArgs,
+	Run:   convertRun,
+}
+
+func init() {
+	convertCmd.Flags().StringVar(&convertParams.From, "from", "", "input format: toml|json|yaml")
+	convertCmd.Flags().StringVar(&convertParams.To, "to", "", "output format: toml|json|yaml")
+	convertCmd.Flags().StringVarP(&convertParams.Input, "input", "i", "", "input file path or URL, \"-\" or empty reads stdin")
+	convertCmd.Flags().StringVarP(&convertParams.Output, "output", "o", "", "output path")
+	convertCmd.Flags().BoolVar(&convertParams.Compact, "compact", false, "print json on a single line")
+	convertCmd.Flags().StringVar(&convertParams.Datetime, "datetime", pkg.DatetimeString, "datetime rendering for json/yaml: string|unix|tagged")
+	convertCmd.Flags().StringVarP(&convertParams.Query, "query", "q", "", "jq style query run on every document before converting, e.g. '.items[] | .name'")
+	rootCmd.AddCommand(convertCmd)
+}
+
+func convertRun(cmd *cobra.Command, args []string) {
+	from := convertParams.From
+	if len(from) == 0 {
+		from = pkg.DetectFormat(convertParams.Input)
+	}
+	if len(from) == 0 {
+		fail(codeUsage, exitUsage, "cannot detect input format, use --from")
+	}
+	to := convertParams.To
+	if len(to) == 0 {
+		to = pkg.DetectFormat(convertParams.Output)
+	}
+	if len(to) == 0 {
+		to = pkg.FormatJSON
+	}
+
+	name, src, err := readInput(convertParams.Input)
+	if err != nil {
+		failRead(name, err)
+	}
+	docs, err := pkg.DecodeSource(name, src, from)
+	if err != nil {
+		failFile(name, err, exitSyntax, fmt.Sprintf("parse %s error", from))
+	}
+
+	queried := len(convertParams.Query) > 0
+	if queried {
+		if docs, err = convertQuery(docs, convertParams.Query); err != nil {
+			failFile(name, err, exitData, "")
+		}
+		if len(docs) == 0 {
+			return
+		}
+	}
+
+	opts := pkg.RenderOptions{Compact: convertParams.Compact, Datetime: convertParams.Datetime}
+	var out string
+	switch {
+	case len(docs) > 1 && to == pkg.FormatTOML:
+		fail(codeData, exitData, "input has %d documents but a toml file holds a single table, convert to json or yaml instead", len(docs))
+	case len(docs) > 1 && (to == pkg.FormatYAML || queried):
+		// 多个yaml文档仍然以---分隔输出，查询结果与jq一样每个结果单独输出
+		sep := "\n"
+		if to == pkg.FormatYAML {
+			sep = "\n---\n"
+		}
+		parts := make([]string, len(docs))
+		for i, doc := range docs {
+			if parts[i], err = pkg.RenderValueWith(doc, to, opts); err != nil {
+				break
+			}
+		}
+		out = strings.Join(parts, sep)
+	case len(docs) > 1:
+		// json中多个文档输出为数组
+		out, err = pkg.RenderValueWith(docs, to, opts)
+	default:
+		if to == pkg.FormatTOML {
+			if err := pkg.CheckNull(docs[0]); err != nil {
+				failFile(name, err, exitData, "convert error")
+			}
+			if _, ok := docs[0].(map[string]any); !ok {
+				fail(codeData, exitData, "toml documents must be a table at the top level")
+			}
+		}
+		out, err = pkg.RenderValueWith(docs[0], to, opts)
+	}
+	if err != nil {
+		failFile(name, err, exitData, "convert error")
+	}
+	if useColor(convertParams.Output) {
+		out = pkg.Colorize(out, to)
+	}
+	writeOutput(convertParams.Output, out)
+}
+
+// convertQuery 对每个文档执行查询，返回所有结果，查询表达式有误时直接退出
+func convertQuery(docs []any, query string) ([]any, error) {
+	expr, err := expandAlias(query)
+	if err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	q, err := pkg.CompileQuery(expr)
+	if err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	var results []any
+	for _, doc := range docs {
+		out, err := q.Run(doc)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, out...)
+	}
+	return results, nil
+}
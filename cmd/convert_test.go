@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestConvertQuery -q对json和yaml输入同样生效
+func TestConvertQuery(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "items.json", `{"items": [{"name": "a", "enabled": true}, {"name": "b", "enabled": false}]}`)
+	writeFile(t, dir, "deploy.yaml", "kind: Service\nmetadata:\n  name: web\n---\nkind: Deployment\nmetadata:\n  name: api\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"json input", []string{"convert", "-i", "items.json", "-q", ".items[] | select(.enabled) | .name"}, `"a"`},
+		{"json to yaml", []string{"convert", "-i", "items.json", "-q", ".items[1]", "--to", "yaml"}, "enabled: false\nname: b"},
+		{"yaml documents", []string{"convert", "-i", "deploy.yaml", "-q", ".metadata.name"}, "\"web\"\n\"api\""},
+		{"yaml to toml", []string{"convert", "-i", "deploy.yaml", "-q", `select(.kind == "Deployment") | .metadata`, "--to", "toml"}, `name = "api"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, code := runAq(t, dir, "", tt.args...)
+			if code != exitOK {
+				t.Fatalf("aq %s exited %d: %s", strings.Join(tt.args, " "), code, stderr)
+			}
+			if got := strings.TrimSpace(out); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+)
+
+// errorFormat 错误和校验结果的输出方式: text|json
+var errorFormat string
+
+// 诊断信息的分类
+const (
+	codeSyntax      = "syntax"        // toml语法错误
+	codeIO          = "io"            // 读取文件失败
+	codeUsage       = "usage"         // 参数或者查询表达式有误
+	codeSchema      = "schema"        // 不满足schema，具体的规则追加在后面，如 schema/required
+	codeKeyNotFound = "key-not-found" // 查找的key不存在
+	codeData        = "data"          // 其他数据错误，如查询表达式运行失败
+
+	codeMissingKey      = "missing-key"      // 要删除的key不存在
+	codeEnvConflict     = "env-conflict"     // 多个key生成了同名的环境变量
+	codeSkipped         = "skipped"          // 无法处理而被跳过的值
+	codeEmptyDir        = "empty-dir"        // 递归的目录中没有匹配的文件
+	codeCommentsDropped = "comments-dropped" // 原地修改时丢失了原文件中的注释
+	codeConfig          = "config"           // 配置文件有误，已忽略
+)
+
+// diagnostic 一条诊断信息，--error-format json时每条输出为一行json
+type diagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Col      int    `json:"col,omitempty"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// printDiagnostic 按--error-format输出诊断信息到标准错误，避免与结果混在一起，text为文本模式下的输出
+func printDiagnostic(d diagnostic, text string) {
+	switch errorFormat {
+	case "text":
+		fmt.Fprintln(os.Stderr, text)
+		return
+	case "json":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown error format %q, want one of text|json\n", errorFormat)
+		os.Exit(exitUsage)
+	}
+	if len(d.Severity) == 0 {
+		d.Severity = "error"
+	}
+	raw, _ := json.Marshal(d)
+	fmt.Fprintln(os.Stderr, string(raw))
+}
+
+// fail 输出与具体输入无关的错误（如参数有误）并以exit退出
+func fail(code string, exit int, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	printDiagnostic(diagnostic{Code: code, Message: msg}, msg)
+	os.Exit(exit)
+}
+
+// failFile 输出处理file时的错误并以exit退出，prefix为文本模式下错误前的说明，可以为空
+func failFile(file string, err error, exit int, prefix string) {
+	text := err.Error()
+	if len(prefix) > 0 {
+		text = prefix + ": " + text
+	}
+	printDiagnostic(fileDiagnostic(file, err), text)
+	os.Exit(exit)
+}
+
+// failRead 输出读取输入失败的错误并以参数错误退出
+func failRead(file string, err error) {
+	d := fileDiagnostic(file, err)
+	d.Code = codeIO
+	printDiagnostic(d, fmt.Sprint("read input error: ", err))
+	os.Exit(exitUsage)
+}
+
+// keyNotFoundError 查找的key不存在
+type keyNotFoundError struct {
+	paths []string
+}
+
+func (e *keyNotFoundError) Error() string {
+	return "key not found: " + strings.Join(e.paths, ", ")
+}
+
+// fileDiagnostic 将处理文件时的错误转换为诊断信息，语法错误带有行列号
+func fileDiagnostic(file string, err error) diagnostic {
+	if se, ok := pkg.AsSyntaxError(err); ok {
+		return diagnostic{File: file, Line: se.Line, Col: se.Col, Code: codeSyntax, Message: se.Message, Hint: pkg.SyntaxHint(se.Message)}
+	}
+	var keyErr *keyNotFoundError
+	switch {
+	case errors.As(err, &keyErr):
+		return diagnostic{File: file, Code: codeKeyNotFound, Message: err.Error()}
+	case exitCode(err) == exitUsage:
+		return diagnostic{File: file, Code: codeIO, Message: err.Error()}
+	}
+	return diagnostic{File: file, Code: codeData, Message: err.Error()}
+}
+
+// reportFileError 输出处理某个文件时的错误，带源码片段的语法错误已经包含文件名
+func reportFileError(file string, err error) {
+	text := fmt.Sprintf("%s: %s", file, err)
+	var se *pkg.SourceError
+	if errors.As(err, &se) {
+		text = err.Error()
+	}
+	printDiagnostic(fileDiagnostic(file, err), text)
+}
+
+// reportParseError 输出单个输入解析失败的错误
+func reportParseError(file string, err error) {
+	printDiagnostic(fileDiagnostic(file, err), fmt.Sprint("parse toml error: ", err))
+}
+
+// warn 输出警告到标准错误，--strict时作为错误处理并以数据错误退出，--quiet时不输出
+func warn(file, code, format string, args ...any) {
+	d := diagnostic{File: file, Code: code, Message: fmt.Sprintf(format, args...), Severity: "warning"}
+	if strictMode {
+		d.Severity = "error"
+	}
+	if !quietOutput || strictMode {
+		if errorFormat == "json" {
+			raw, _ := json.Marshal(d)
+			fmt.Fprintln(os.Stderr, string(raw))
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", d.Severity, d.Message)
+		}
+	}
+	if strictMode {
+		os.Exit(exitData)
+	}
+}
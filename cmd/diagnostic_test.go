@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestErrorsStayOffStdout 出错时标准输出为空，--error-format json时标准错误的每一行都是诊断信息
+func TestErrorsStayOffStdout(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "bad.toml", "a = \n")
+	writeFile(t, dir, "ok.json", `{"a": 1}`)
+	writeFile(t, dir, "ok.toml", "a = 1\n")
+
+	tests := []struct {
+		name string
+		args []string
+		code string
+	}{
+		{"convert syntax", []string{"convert", "-i", "bad.toml"}, codeSyntax},
+		{"convert bad query", []string{"convert", "-i", "ok.json", "-q", ".["}, codeUsage},
+		{"convert missing input", []string{"convert", "-i", "missing.toml"}, codeIO},
+		{"convert unknown format", []string{"convert", "-i", "ok.txt"}, codeUsage},
+		{"toml bad query", []string{"toml", "-q", ".[", "ok.toml"}, codeUsage},
+		{"get missing key", []string{"toml", "get", "b", "ok.toml"}, codeKeyNotFound},
+		{"set bad path", []string{"toml", "set", "a[", "1", "ok.toml"}, codeUsage},
+		{"render missing data", []string{"render", "-d", "missing.json"}, codeIO},
+		{"gen-go bad type", []string{"gen-go", "--type", "lower", "ok.toml"}, codeUsage},
+		{"tree bad style", []string{"toml", "tree", "--style", "x", "ok.toml"}, codeUsage},
+		{"unknown flag", []string{"toml", "--bogus"}, codeUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--error-format", "json"}, tt.args...)
+			out, stderr, code := runAq(t, dir, "", args...)
+			if code == exitOK {
+				t.Fatalf("aq %s succeeded", strings.Join(tt.args, " "))
+			}
+			if len(out) > 0 {
+				t.Fatalf("stdout is not empty: %q", out)
+			}
+			lines := strings.Split(strings.TrimSpace(stderr), "\n")
+			var d diagnostic
+			if err := json.Unmarshal([]byte(lines[len(lines)-1]), &d); err != nil {
+				t.Fatalf("stderr is not a json diagnostic: %q", stderr)
+			}
+			if d.Code != tt.code {
+				t.Fatalf("code = %q, want %q: %s", d.Code, tt.code, stderr)
+			}
+		})
+	}
+}
+
+// TestJSONSyntaxDiagnostic json和yaml的语法错误与toml一样带有位置和源码片段
+func TestJSONSyntaxDiagnostic(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "bad.json", "{\n  \"a\": 1,\n  \"b\": ,\n}\n")
+
+	_, stderr, code := runAq(t, dir, "", "--error-format", "json", "convert", "-i", "bad.json")
+	if code != exitSyntax {
+		t.Fatalf("exit code = %d, want %d", code, exitSyntax)
+	}
+	var d diagnostic
+	if err := json.Unmarshal([]byte(strings.TrimSpace(stderr)), &d); err != nil {
+		t.Fatalf("stderr is not a json diagnostic: %q", stderr)
+	}
+	if d.Code != codeSyntax || d.File != "bad.json" || d.Line != 3 || d.Col != 8 {
+		t.Fatalf("diagnostic = %+v, want a syntax error at bad.json:3:8", d)
+	}
+
+	_, stderr, _ = runAq(t, dir, "", "gen-go", "bad.json")
+	if !strings.Contains(stderr, "bad.json:3:8:") || !strings.Contains(stderr, `3 |   "b": ,`) {
+		t.Fatalf("gen-go error has no source snippet: %q", stderr)
+	}
+}
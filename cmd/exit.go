@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/dzjyyds666/aq/pkg"
+)
+
+// 所有命令统一使用的退出码
+const (
+	exitOK     = 0 // 成功
+	exitData   = 1 // 数据错误，如key不存在、校验未通过、存在差异
+	exitSyntax = 2 // 输入的语法错误
+	exitUsage  = 3 // 参数错误或者读写文件失败
+)
+
+// exitCode 根据错误的类型返回对应的退出码
+func exitCode(err error) int {
+	if _, ok := pkg.AsSyntaxError(err); ok {
+		return exitSyntax
+	}
+	var pathErr *fs.PathError
+	var fetchErr *pkg.FetchError
+	if errors.As(err, &pathErr) || errors.As(err, &fetchErr) {
+		return exitUsage
+	}
+	return exitData
+}
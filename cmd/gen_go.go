@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"go/token"
+	"slices"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type GenGoParams struct {
+	From     string   `json:"from"`     // 输入格式
+	Package  string   `json:"package"`  // 生成代码的包名
+	TypeName string   `json:"type"`     // 根结构体的名称
+	Tags     []string `json:"tags"`     // 字段的tag
+	Pointers bool     `json:"pointers"` // 可选字段使用指针
+	Output   string   `json:"output"`   // 输出文件地址
+}
+
+var genGoParams = &GenGoParams{}
+
+var genGoCmd = &cobra.Command{
+	Use:   "gen-go [file]...",
+	Short: "generate Go structs from toml, json or yaml documents",
+	Long:  "Print Go type declarations for the given documents. Tables become structs, fields missing from some of the samples are optional (omitempty, or pointers with --pointers). Tags default to the input format.",
+	Run:   genGoRun,
+}
+
+func init() {
+	genGoCmd.Flags().StringVar(&genGoParams.From, "from", "", "input format: toml|json|yaml, detected from the extension by default")
+	genGoCmd.Flags().StringVar(&genGoParams.Package, "package", "config", "package name of the generated code")
+	genGoCmd.Flags().StringVar(&genGoParams.TypeName, "type", "Config", "name of the root struct")
+	genGoCmd.Flags().StringSliceVar(&genGoParams.Tags, "tags", nil, "struct tags to emit, e.g. toml,json (default the input format)")
+	genGoCmd.Flags().BoolVar(&genGoParams.Pointers, "pointers", false, "use pointer types for optional fields")
+	genGoCmd.Flags().StringVarP(&genGoParams.Output, "output", "o", "", "output path")
+	rootCmd.AddCommand(genGoCmd)
+}
+
+func genGoRun(cmd *cobra.Command, args []string) {
+	if !token.IsIdentifier(genGoParams.Package) || !token.IsIdentifier(genGoParams.TypeName) || !token.IsExported(genGoParams.TypeName) {
+		fail(codeUsage, exitUsage, "--package must be an identifier and --type an exported identifier")
+	}
+	files := args
+	if len(files) == 0 {
+		files = []string{""}
+	}
+
+	inferrer := &pkg.SchemaInferrer{}
+	tags := genGoParams.Tags
+	for _, file := range files {
+		format := genGoParams.From
+		if len(format) == 0 {
+			format = pkg.DetectFormat(file)
+		}
+		if len(format) == 0 {
+			format = pkg.FormatTOML
+		}
+		if len(genGoParams.Tags) == 0 && !slices.Contains(tags, format) {
+			tags = append(tags, format)
+		}
+
+		name, src, err := readInput(file)
+		if err != nil {
+			failRead(name, err)
+		}
+		docs, err := pkg.DecodeSource(name, src, format)
+		if err != nil {
+			failFile(name, err, exitSyntax, "parse "+format+" error")
+		}
+		// 多文档的yaml中每个文档都是一个示例
+		for _, doc := range docs {
+			table, ok := doc.(map[string]any)
+			if !ok {
+				fail(codeData, exitData, "%s: top level must be a table, got %s", name, pkg.TypeName(doc))
+			}
+			inferrer.Observe(table)
+		}
+	}
+
+	out, err := pkg.GenerateGo(inferrer.Schema(), pkg.GoOptions{
+		Package:  genGoParams.Package,
+		TypeName: genGoParams.TypeName,
+		Tags:     tags,
+		Pointers: genGoParams.Pointers,
+	})
+	if err != nil {
+		fail(codeData, exitData, "%s", err)
+	}
+	writeOutput(genGoParams.Output, out)
+}
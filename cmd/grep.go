@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type GrepParams struct {
+	KeysOnly   bool     `json:"keys_only"`   // 只匹配key路径
+	ValuesOnly bool     `json:"values_only"` // 只匹配标量的值
+	IgnoreCase bool     `json:"ignore_case"` // 忽略大小写
+	Recursive  bool     `json:"recursive"`   // 输入为目录时递归搜索其中的文件
+	Include    []string `json:"include"`     // 递归时只搜索匹配的文件
+	Exclude    []string `json:"exclude"`     // 递归时跳过匹配的文件和目录
+	Output     string   `json:"output"`      // 输出文件地址
+}
+
+var grepParams = &GrepParams{}
+
+var grepCmd = &cobra.Command{
+	Use:   "grep <pattern> [file]...",
+	Short: "search key paths and values across toml files",
+	Long:  "Match a regular expression against key paths and scalar values and print every hit as file:path = value. Comments and formatting are ignored. Exits 1 when nothing matches, 2 on syntax errors and 3 on IO errors.",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   grepRun,
+}
+
+func init() {
+	grepCmd.Flags().BoolVar(&grepParams.KeysOnly, "keys-only", false, "only match key paths")
+	grepCmd.Flags().BoolVar(&grepParams.ValuesOnly, "values-only", false, "only match scalar values")
+	grepCmd.Flags().BoolVar(&grepParams.IgnoreCase, "ignore-case", false, "match case-insensitively")
+	grepCmd.Flags().BoolVarP(&grepParams.Recursive, "recursive", "R", false, "walk directory inputs, honoring .gitignore and .aqignore")
+	grepCmd.Flags().StringArrayVar(&grepParams.Include, "include", []string{"*.toml"}, "with --recursive, only search files matching this glob")
+	grepCmd.Flags().StringArrayVar(&grepParams.Exclude, "exclude", nil, "with --recursive, skip files and directories matching this glob")
+	grepCmd.Flags().StringVarP(&grepParams.Output, "output", "o", "", "output path")
+	grepCmd.MarkFlagsMutuallyExclusive("keys-only", "values-only")
+	rootCmd.AddCommand(grepCmd)
+}
+
+func grepRun(cmd *cobra.Command, args []string) {
+	pattern := args[0]
+	if grepParams.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		fail(codeUsage, exitUsage, "invalid pattern: %s", err)
+	}
+	params.Recursive = grepParams.Recursive
+	params.Include = grepParams.Include
+	params.Exclude = grepParams.Exclude
+	params.Output = grepParams.Output
+	if err := resolveInputs(args[1:]...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	files := params.Inputs
+	if len(files) == 0 {
+		files = []string{""}
+	}
+
+	var lines []string
+	code := eachFile(files, func(file string) func() int {
+		name, src, err := readInput(file)
+		var data map[string]any
+		if err == nil {
+			data, err = pkg.DecodeTomlSource(name, src)
+		}
+		if err != nil {
+			return func() int {
+				reportFileError(name, err)
+				return exitCode(err)
+			}
+		}
+		var hits []string
+		for _, m := range pkg.Grep(data, re, !grepParams.ValuesOnly, !grepParams.KeysOnly) {
+			line := name + ":" + pkg.FormatPath(m.Path)
+			if pkg.IsScalar(m.Value) {
+				line += " = " + pkg.InlineValue(m.Value)
+			}
+			hits = append(hits, line)
+		}
+		return func() int {
+			lines = append(lines, hits...)
+			return exitOK
+		}
+	})
+
+	if len(lines) > 0 {
+		writeResult(strings.Join(lines, "\n"))
+	}
+	switch {
+	case code != exitOK:
+		os.Exit(code)
+	case len(lines) == 0:
+		os.Exit(exitData)
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+// resolvedInputs params.Inputs中已经展开过的个数，PersistentPreRun和子命令都会调用resolveInputs，每个输入只展开一次
+var resolvedInputs int
+
+// resolveInputs 合并-i参数和位置参数中的文件，展开glob和目录，只有一个输入时同时设置params.Input
+func resolveInputs(extra ...string) error {
+	done := params.Inputs[:resolvedInputs:resolvedInputs]
+	var matched []string
+	for _, pattern := range append(append([]string(nil), params.Inputs[resolvedInputs:]...), extra...) {
+		if pkg.IsURL(pattern) || !strings.ContainsAny(pattern, "*?[") {
+			matched = append(matched, pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("no files match %q", pattern)
+		}
+		matched = append(matched, matches...)
+	}
+
+	var files []string
+	for _, file := range matched {
+		if pkg.IsURL(file) {
+			files = append(files, file)
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil || !info.IsDir() {
+			files = append(files, file)
+			continue
+		}
+		if !params.Recursive {
+			return fmt.Errorf("%s is a directory, use --recursive", file)
+		}
+		walked, err := pkg.WalkFiles(file, params.Include, params.Exclude)
+		if err != nil {
+			return err
+		}
+		if len(walked) == 0 {
+			warn(file, codeEmptyDir, "no files in %s match %s", file, strings.Join(params.Include, ", "))
+		}
+		files = append(files, walked...)
+	}
+
+	params.Inputs = append(done, files...)
+	resolvedInputs = len(params.Inputs)
+	params.Input = ""
+	if len(params.Inputs) == 1 {
+		params.Input = params.Inputs[0]
+	}
+	return nil
+}
+
+// splitInputArgs 将位置参数中已存在的文件、url和glob拆分为输入文件，可以在key之前或之后，如 get server.port configs/*.toml；
+// 使用-i指定输入时所有位置参数都不是文件。参数校验、执行和补全都用它拆分，保证三者一致
+func splitInputArgs(args []string) (rest, files []string) {
+	if len(params.Inputs) > 0 {
+		return args, nil
+	}
+	for _, arg := range args {
+		if exist, _ := pkg.CheckFileExist(arg); exist || pkg.IsURL(arg) || strings.ContainsAny(arg, "*?") {
+			files = append(files, arg)
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, files
+}
+
+// inputArgs 去掉位置参数中的输入文件后再用check校验其余参数
+func inputArgs(check cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		rest, _ := splitInputArgs(args)
+		return check(cmd, rest)
+	}
+}
+
+// runInputs 对每个输入执行render；多个文件时按--jobs并发处理，每行结果前加上文件名，全部处理完后按最严重的错误退出
+func runInputs(render func(data map[string]any) (string, error)) {
+	if len(params.Inputs) <= 1 {
+		data, _ := loadToml()
+		out, err := render(data)
+		if len(out) > 0 {
+			writeResult(out)
+		}
+		if err != nil {
+			if !quietOutput || exitCode(err) != exitData {
+				name := params.Input
+				if len(name) == 0 || name == "-" {
+					name = "<stdin>"
+				}
+				printDiagnostic(fileDiagnostic(name, err), err.Error())
+			}
+			os.Exit(exitCode(err))
+		}
+		return
+	}
+
+	var lines []string
+	code := eachFile(params.Inputs, func(file string) func() int {
+		var out string
+		_, src, err := readInput(file)
+		if err == nil {
+			var data map[string]any
+			if data, err = pkg.DecodeTomlSource(file, src); err == nil {
+				out, err = render(data)
+			}
+		}
+		return func() int {
+			if len(out) > 0 {
+				for _, line := range strings.Split(out, "\n") {
+					lines = append(lines, file+": "+line)
+				}
+			}
+			if err == nil {
+				return exitOK
+			}
+			if !quietOutput || exitCode(err) != exitData {
+				reportFileError(file, err)
+			}
+			return exitCode(err)
+		}
+	})
+	if len(lines) > 0 {
+		writeResult(strings.Join(lines, "\n"))
+	}
+	if code != exitOK {
+		os.Exit(code)
+	}
+}
+
+// readInput 读取输入文件的内容，路径为空或"-"时读取标准输入，http(s)地址通过网络读取，压缩的内容会自动解压，
+// 同时返回用于错误信息的名称
+func readInput(file string) (string, []byte, error) {
+	name, src, err := readRawInput(file)
+	if err != nil {
+		return name, nil, err
+	}
+	src, err = pkg.Decompress(src, fetchOptions.MaxSize)
+	if err != nil {
+		return name, nil, fmt.Errorf("decompress %s: %w", name, err)
+	}
+	return name, src, nil
+}
+
+// readRawInput 读取输入的原始内容
+func readRawInput(file string) (string, []byte, error) {
+	if len(file) == 0 || file == "-" {
+		src, err := io.ReadAll(os.Stdin)
+		return "<stdin>", src, err
+	}
+	if pkg.IsURL(file) {
+		opts := fetchOptions
+		if len(opts.Token) == 0 {
+			opts.Token = os.Getenv("AQ_TOKEN")
+		}
+		src, err := pkg.FetchURL(file, opts)
+		return file, src, err
+	}
+	src, err := os.ReadFile(file)
+	return file, src, err
+}
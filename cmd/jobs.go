@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"runtime"
+)
+
+// parallelJobs 同时处理的文件数量
+var parallelJobs int
+
+func init() {
+	rootCmd.PersistentFlags().IntVarP(&parallelJobs, "jobs", "j", runtime.NumCPU(), "number of files processed concurrently, output keeps the input order")
+}
+
+// eachFile 用--jobs个goroutine并发执行work，work返回的函数负责输出结果，按files的顺序在当前goroutine中依次调用，
+// 返回其中最大的退出码
+func eachFile(files []string, work func(file string) func() int) int {
+	results := make([]chan func() int, len(files))
+	for i := range results {
+		results[i] = make(chan func() int, 1)
+	}
+	next := make(chan int)
+	go func() {
+		for i := range files {
+			next <- i
+		}
+		close(next)
+	}()
+	for range min(max(parallelJobs, 1), len(files)) {
+		go func() {
+			for i := range next {
+				results[i] <- work(files[i])
+			}
+		}()
+	}
+
+	code := exitOK
+	for _, result := range results {
+		code = max(code, (<-result)())
+	}
+	return code
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// 设置该环境变量时测试二进制作为aq运行，用于在子进程中执行完整的命令行
+const runAqEnv = "AQ_TEST_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runAqEnv) == "1" {
+		Execute()
+		os.Exit(exitOK)
+	}
+	os.Exit(m.Run())
+}
+
+// testConfig runAq使用的配置文件名，位于dir中，默认不存在，需要时由测试写入
+const testConfig = "aq-config.toml"
+
+// runAq 在dir中以子进程运行aq，返回标准输出、标准错误和退出码；stdin为空时不提供标准输入
+func runAq(t *testing.T, dir, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), runAqEnv+"=1", "AQ_CONFIG="+filepath.Join(dir, testConfig))
+	if len(stdin) > 0 {
+		cmd.Stdin = bytes.NewBufferString(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return stdout.String(), stderr.String(), exitErr.ExitCode()
+	case err != nil:
+		t.Fatal(err)
+	}
+	return stdout.String(), stderr.String(), exitOK
+}
+
+// writeFile 在dir中写入测试文件
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// pluginPrefix 插件可执行文件的前缀，aq-vault 对应 aq vault
+const pluginPrefix = "aq-"
+
+// pluginMetadataFlag 插件收到该参数时应向标准输出打印一个PluginMetadata的json
+const pluginMetadataFlag = "--aq-metadata"
+
+// PluginMetadata 插件自我描述的信息
+type PluginMetadata struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+// pluginInfo 在PATH中找到的插件，只根据文件名得到，不会运行插件
+type pluginInfo struct {
+	Name string
+	Path string
+}
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "manage external aq-<name> subcommands",
+	Long:  "Executables named aq-<name> on PATH are run as \"aq <name>\" with the remaining arguments, inheriting stdin, stdout and stderr. Global settings are passed in AQ_* environment variables.",
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list plugins found on PATH",
+	Long:  "List aq-<name> executables on PATH by file name. Plugins are not run, use \"aq plugin info <name>\" to ask one for its description.",
+	Args:  cobra.NoArgs,
+	Run:   pluginListRun,
+}
+
+var pluginInfoCmd = &cobra.Command{
+	Use:   "info <name>",
+	Short: "run a plugin with " + pluginMetadataFlag + " and print its description",
+	Args:  cobra.ExactArgs(1),
+	Run:   pluginInfoRun,
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	pluginCmd.AddCommand(pluginInfoCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
+
+func pluginListRun(cmd *cobra.Command, args []string) {
+	plugins := findPlugins()
+	if len(outputFormat) > 0 {
+		writeResult(renderResult(pluginsValue(plugins), outputFormat))
+		return
+	}
+	if len(plugins) == 0 {
+		return
+	}
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	for _, p := range plugins {
+		fmt.Fprintf(w, "%s\t%s\n", p.Name, p.Path)
+	}
+	w.Flush()
+	writeResult(strings.TrimRight(sb.String(), "\n"))
+}
+
+// pluginsValue 将插件列表转换为可以按json、yaml或toml输出的值
+func pluginsValue(plugins []pluginInfo) map[string]any {
+	list := []any{}
+	for _, p := range plugins {
+		list = append(list, map[string]any{"name": p.Name, "path": p.Path})
+	}
+	return map[string]any{"plugins": list}
+}
+
+// findPlugins 按PATH的顺序查找插件，同名插件只取第一个
+func findPlugins() []pluginInfo {
+	seen := map[string]bool{}
+	var plugins []pluginInfo
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || seen[name] || entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, pluginInfo{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// pluginName 从可执行文件名中取出插件名
+func pluginName(file string) (string, bool) {
+	name, ok := strings.CutPrefix(file, pluginPrefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name, ok && len(name) > 0
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0111 != 0
+}
+
+func pluginInfoRun(cmd *cobra.Command, args []string) {
+	path, err := exec.LookPath(pluginPrefix + args[0])
+	if err != nil {
+		fail(codeUsage, exitUsage, "plugin %q not found on PATH", args[0])
+	}
+	meta, err := pluginMetadata(path)
+	if err != nil {
+		fail(codeData, exitData, "read plugin metadata error: %s", err)
+	}
+	value := map[string]any{"name": meta.Name, "description": meta.Description, "version": meta.Version, "path": path}
+	format := outputFormat
+	if len(format) == 0 {
+		format = pkg.FormatJSON
+	}
+	writeResult(renderResult(value, format))
+}
+
+// pluginMetadata 调用插件获取描述信息，只在明确指定插件时调用
+func pluginMetadata(path string) (PluginMetadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var meta PluginMetadata
+	out, err := exec.CommandContext(ctx, path, pluginMetadataFlag).Output()
+	if err != nil {
+		return meta, err
+	}
+	if err := json.Unmarshal(out, &meta); err != nil {
+		return meta, fmt.Errorf("plugin does not print json for %s: %w", pluginMetadataFlag, err)
+	}
+	return meta, nil
+}
+
+// splitGlobalFlags 跳过子命令之前的全局参数，返回全局参数和其余参数，遇到未知参数时ok为false
+func splitGlobalFlags(args []string) (globals, rest []string, ok bool) {
+	flags := rootCmd.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") || arg == "-" {
+			return args[:i], args[i:], true
+		}
+		var flag *pflag.Flag
+		attached := strings.Contains(arg, "=")
+		if name, isLong := strings.CutPrefix(arg, "--"); isLong {
+			name, _, _ = strings.Cut(name, "=")
+			flag = flags.Lookup(name)
+		} else {
+			flag = flags.ShorthandLookup(arg[1:2])
+			attached = attached || len(arg) > 2
+		}
+		if flag == nil {
+			return nil, nil, false
+		}
+		if flag.Value.Type() != "bool" && !attached {
+			i++
+		}
+	}
+	return args, nil, true
+}
+
+// runPluginIfExternal 全局参数之后的第一个参数不是内置命令且PATH中存在对应插件时运行插件并以其退出码退出
+func runPluginIfExternal(args []string) {
+	globals, args, ok := splitGlobalFlags(args)
+	if !ok || len(args) == 0 || args[0] == "--" {
+		return
+	}
+	if cmd, _, err := rootCmd.Find(args); err == nil && cmd != rootCmd {
+		return
+	}
+	path, err := exec.LookPath(pluginPrefix + args[0])
+	if err != nil {
+		return
+	}
+	// 插件之前的全局参数需要先解析，才能通过环境变量传给插件
+	if err := rootCmd.PersistentFlags().Parse(globals); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+
+	plugin := exec.Command(path, args[1:]...)
+	plugin.Stdin, plugin.Stdout, plugin.Stderr = os.Stdin, os.Stdout, os.Stderr
+	plugin.Env = append(os.Environ(), pluginEnv()...)
+	if err := plugin.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		fail(codeUsage, exitUsage, "run plugin error: %s", err)
+	}
+	os.Exit(exitOK)
+}
+
+// pluginEnv 传给插件的全局设置
+func pluginEnv() []string {
+	self, _ := os.Executable()
+	return []string{
+		"AQ_BIN=" + self,
+		"AQ_CONFIG=" + pkg.ConfigPath(),
+		"AQ_OUTPUT_FORMAT=" + outputFormat,
+		"AQ_COLOR=" + colorMode,
+		"AQ_ERROR_FORMAT=" + errorFormat,
+		fmt.Sprintf("AQ_STRICT=%t", strictMode),
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type RenderParams struct {
+	Data       string `json:"data"`        // 数据文件路径
+	From       string `json:"from"`        // 数据文件格式
+	Output     string `json:"output"`      // 输出文件地址
+	Document   int    `json:"document"`    // 多文档的yaml中使用第几个文档，从0开始
+	StrictVars bool   `json:"strict_vars"` // 引用不存在的key时报错
+}
+
+var renderParams = &RenderParams{}
+
+var renderCmd = &cobra.Command{
+	Use:   "render -d <data> [template]",
+	Short: "render a Go text/template with data from a toml, json or yaml file",
+	Long:  "Parse the data file and execute the template against it. Sprig-style helpers such as default, upper, quote, indent, join and toJson are available. The template is read from stdin when no file is given.",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   renderRun,
+}
+
+func init() {
+	renderCmd.Flags().StringVarP(&renderParams.Data, "data", "d", "", "data file path or URL")
+	renderCmd.Flags().StringVar(&renderParams.From, "from", "", "data format: toml|json|yaml, detected from the extension by default")
+	renderCmd.Flags().StringVarP(&renderParams.Output, "output", "o", "", "output path")
+	renderCmd.Flags().BoolVar(&renderParams.StrictVars, "strict-vars", false, "fail when the template references a missing key")
+	renderCmd.Flags().IntVar(&renderParams.Document, "document", -1, "index of the yaml document to use when the data file has several, starting at 0")
+	renderCmd.MarkFlagRequired("data")
+	rootCmd.AddCommand(renderCmd)
+}
+
+func renderRun(cmd *cobra.Command, args []string) {
+	data, err := loadRenderData(renderParams.Data, renderParams.From)
+	if err != nil {
+		failFile(renderParams.Data, err, exitCode(err), "load data error")
+	}
+
+	name := "<stdin>"
+	var text []byte
+	if len(args) == 0 || args[0] == "-" {
+		text, err = io.ReadAll(os.Stdin)
+	} else {
+		name = filepath.Base(args[0])
+		text, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		fail(codeIO, exitUsage, "read template error: %s", err)
+	}
+
+	tmpl := template.New(name).Funcs(pkg.TemplateFuncs())
+	if renderParams.StrictVars {
+		tmpl = tmpl.Option("missingkey=error")
+	}
+	if _, err := tmpl.Parse(string(text)); err != nil {
+		fail(codeSyntax, exitSyntax, "parse template error: %s", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		fail(codeData, exitData, "render template error: %s", err)
+	}
+
+	// 模板的输出原样写出，不追加换行
+	if len(renderParams.Output) == 0 {
+		if !quietOutput {
+			os.Stdout.Write(buf.Bytes())
+		}
+		return
+	}
+	writeOutputFile(renderParams.Output, buf.Bytes())
+}
+
+// loadRenderData 读取数据文件，未指定格式时按扩展名识别
+func loadRenderData(file, format string) (any, error) {
+	if len(format) == 0 {
+		format = pkg.DetectFormat(file)
+	}
+	if len(format) == 0 {
+		return nil, fmt.Errorf("cannot detect the format of %s, use --from", file)
+	}
+	name, src, err := readInput(file)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := pkg.DecodeSource(name, src, format)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case renderParams.Document >= len(docs):
+		return nil, fmt.Errorf("--document %d is out of range, %s has %d documents", renderParams.Document, file, len(docs))
+	case renderParams.Document >= 0:
+		return docs[renderParams.Document], nil
+	case len(docs) > 1:
+		return nil, fmt.Errorf("%s has %d yaml documents, pick one with --document", file, len(docs))
+	}
+	return docs[0], nil
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+)
+
+var replCmd = &cobra.Command{
+	Use:   "repl <file>",
+	Short: "explore and edit a toml file interactively",
+	Args:  cobra.ExactArgs(1),
+	Run:   replRun,
+}
+
+func init() {
+	rootCmd.AddCommand(replCmd)
+}
+
+// replCommands repl支持的命令及说明
+var replCommands = map[string]string{
+	"get":   "get <path>            print the value at path",
+	"type":  "type <path>           print the type of the value at path",
+	"keys":  "keys [prefix]         list key paths",
+	"query": "query <expr>          run a jq style query",
+	"set":   "set <path> <value> [type]  set a value, type is string|int|float|bool|datetime",
+	"del":   "del <path>            delete a key, table or array element",
+	"save":  "save                  write changes back to the file",
+	"help":  "help                  show this help",
+	"quit":  "quit                  leave, asking to save unsaved changes",
+}
+
+// replTypes set命令末尾可以指定的类型
+var replTypes = map[string]bool{"string": true, "int": true, "float": true, "bool": true, "datetime": true}
+
+// replSession repl的状态
+type replSession struct {
+	file     string
+	data     map[string]any
+	order    pkg.KeyOrder // 保存时沿用文件中key的顺序
+	modified bool
+}
+
+func replRun(cmd *cobra.Command, args []string) {
+	data, order := loadTomlFile(args[0])
+	session := &replSession{file: args[0], data: data, order: order}
+
+	line := liner.NewLiner()
+	defer line.Close()
+	line.SetCtrlCAborts(true)
+	line.SetTabCompletionStyle(liner.TabPrints)
+	line.SetCompleter(session.complete)
+
+	historyFile := replHistoryFile()
+	if f, err := os.Open(historyFile); err == nil {
+		line.ReadHistory(f)
+		f.Close()
+	}
+	defer func() {
+		if f, err := os.Create(historyFile); err == nil {
+			line.WriteHistory(f)
+			f.Close()
+		}
+	}()
+
+	fmt.Printf("aq repl: %s, type help for commands\n", session.file)
+	for {
+		input, err := line.Prompt("aq> ")
+		if errors.Is(err, liner.ErrPromptAborted) || errors.Is(err, io.EOF) {
+			session.quit(line)
+			return
+		}
+		if err != nil {
+			fmt.Println("read input error:", err)
+			return
+		}
+		input = strings.TrimSpace(input)
+		if len(input) == 0 {
+			continue
+		}
+		line.AppendHistory(input)
+
+		name, rest, _ := strings.Cut(input, " ")
+		if name == "quit" || name == "exit" {
+			session.quit(line)
+			return
+		}
+		if err := session.exec(name, strings.TrimSpace(rest)); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+}
+
+// exec 执行一条命令
+func (s *replSession) exec(name, rest string) error {
+	switch name {
+	case "help":
+		names := make([]string, 0, len(replCommands))
+		for n := range replCommands {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Println("  " + replCommands[n])
+		}
+	case "get", "type":
+		value, ok, err := pkg.FindValue(s.data, rest)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("key not found: %s", rest)
+		}
+		if name == "type" {
+			fmt.Println(pkg.TypeName(value))
+			return nil
+		}
+		out, err := pkg.FormatValue(value)
+		if err != nil {
+			return err
+		}
+		fmt.Println(out)
+	case "keys":
+		pkg.Walk(s.data, func(path []pkg.PathKey, value any) bool {
+			if p := pkg.FormatPath(path); strings.HasPrefix(p, rest) {
+				fmt.Println(p)
+			}
+			return true
+		})
+	case "query":
+		expr, err := expandAlias(rest)
+		if err != nil {
+			return err
+		}
+		query, err := pkg.CompileQuery(expr)
+		if err != nil {
+			return err
+		}
+		results, err := query.Run(s.data)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			out, err := pkg.RenderValue(result, pkg.FormatJSON)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+		}
+	case "set":
+		// 值可能包含空格，如 set title "hello world"，只拆出路径，其余作为一个toml值
+		path, literal, _ := strings.Cut(rest, " ")
+		literal = strings.TrimSpace(literal)
+		if len(path) == 0 || len(literal) == 0 {
+			return fmt.Errorf("usage: %s", replCommands["set"])
+		}
+		keys, err := pkg.ParsePath(path)
+		if err != nil {
+			return err
+		}
+		typ := ""
+		if i := strings.LastIndexByte(literal, ' '); i > 0 && replTypes[literal[i+1:]] {
+			literal, typ = strings.TrimSpace(literal[:i]), literal[i+1:]
+		}
+		value, err := pkg.ParseLiteral(literal, typ)
+		if err != nil {
+			return err
+		}
+		// 指定为string时带引号的值按toml字符串解析
+		if quoted, err := pkg.ParseLiteral(literal, ""); typ == "string" && err == nil && pkg.TypeName(quoted) == "string" {
+			value = quoted
+		}
+		if err := pkg.SetValue(s.data, keys, value); err != nil {
+			return err
+		}
+		s.modified = true
+	case "del":
+		keys, err := pkg.ParsePath(rest)
+		if err != nil {
+			return err
+		}
+		found, err := pkg.DeleteValue(s.data, keys, false)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("key not found: %s", rest)
+		}
+		s.modified = true
+	case "save":
+		return s.save()
+	default:
+		return fmt.Errorf("unknown command %q, type help for commands", name)
+	}
+	return nil
+}
+
+// save 原子写回文件，标准输入、url和压缩文件无法写回
+func (s *replSession) save() error {
+	if err := checkWritable(s.file); err != nil {
+		return fmt.Errorf("save %w", err)
+	}
+	out, err := pkg.EncodeTomlOrdered(s.data, s.order)
+	if err != nil {
+		return err
+	}
+	if err := pkg.WriteFileAtomic(s.file, []byte(out+"\n"), ""); err != nil {
+		return err
+	}
+	s.modified = false
+	fmt.Println("saved", s.file)
+	return nil
+}
+
+// quit 有未保存的修改时询问是否保存
+func (s *replSession) quit(line *liner.State) {
+	if !s.modified {
+		return
+	}
+	answer, err := line.Prompt("save changes to " + s.file + "? [y/N] ")
+	if err != nil || !strings.EqualFold(strings.TrimSpace(answer), "y") {
+		return
+	}
+	if err := s.save(); err != nil {
+		fmt.Println("error:", err)
+	}
+}
+
+// complete 第一个词补全命令，之后补全key路径
+func (s *replSession) complete(line string) []string {
+	name, rest, hasArg := strings.Cut(line, " ")
+	var out []string
+	if !hasArg {
+		for n := range replCommands {
+			if strings.HasPrefix(n, name) {
+				out = append(out, n+" ")
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+	if strings.Contains(rest, " ") {
+		return nil
+	}
+	pkg.Walk(s.data, func(path []pkg.PathKey, value any) bool {
+		if p := pkg.FormatPath(path); strings.HasPrefix(p, rest) {
+			out = append(out, name+" "+p)
+		}
+		return true
+	})
+	return out
+}
+
+// replHistoryFile 历史记录保存在用户缓存目录下
+func replHistoryFile() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "aq_repl_history")
+	}
+	os.MkdirAll(filepath.Join(dir, "aq"), 0755)
+	return filepath.Join(dir, "aq", "repl_history")
+}
@@ -3,7 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/dzjyyds666/aq/pkg"
 	"github.com/spf13/cobra"
 )
 
@@ -14,25 +17,81 @@ var rootCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
+	// 参数错误统一由Execute按--error-format输出到标准错误
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	// 配置文件有误时只给出警告并使用默认设置，--help和version等命令仍然可以使用
+	if err := applyUserConfig(); err != nil {
+		warn(pkg.ConfigPath(), codeConfig, "ignoring config file: %s", err)
+	}
+	runPluginIfExternal(os.Args[1:])
+	if cmd, err := rootCmd.ExecuteC(); err != nil {
+		msg := err.Error()
+		printDiagnostic(diagnostic{Code: codeUsage, Message: msg}, fmt.Sprintf("Error: %s\nRun '%s --help' for usage.", msg, cmd.CommandPath()))
+		os.Exit(exitUsage)
 	}
 }
 
-var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Print the version number of Aq",
-	Long:  `All software has versions. This is Aq's`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Aq v0.1 -- HEAD")
-	},
-}
+var (
+	outputFormat string // 全局输出格式
+	rawOutput    bool   // 字符串结果不加引号直接输出
+	colorMode    string // 语法高亮: auto|always|never
+	quietOutput  bool   // 不输出结果，只通过退出码表示成功与否
+	strictMode   bool   // 警告视为错误
+
+	userConfig   = &pkg.Config{}      // 用户配置文件中的设置
+	fetchOptions = pkg.FetchOptions{} // 读取url输入时的设置
+	backupOutput bool                 // 覆盖--output文件前保留带时间戳的备份
+)
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output-format", "", "output format: json|yaml|toml|raw|table|markdown|csv (each command picks its own default)")
+	rootCmd.PersistentFlags().BoolVarP(&rawOutput, "raw", "r", false, "print string results without quotes, like jq -r")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "highlight json/toml output: auto|always|never")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", "text", "how errors and validation results are printed: text|json")
+	rootCmd.PersistentFlags().BoolVar(&quietOutput, "quiet", false, "print nothing on success or data errors, only set the exit code")
+	rootCmd.PersistentFlags().BoolVar(&strictMode, "strict", false, "treat warnings as errors")
+	rootCmd.PersistentFlags().DurationVar(&fetchOptions.Timeout, "http-timeout", 30*time.Second, "timeout for reading URL inputs")
+	rootCmd.PersistentFlags().StringArrayVarP(&fetchOptions.Headers, "header", "H", nil, "extra header for URL inputs, \"Name: value\", repeatable")
+	rootCmd.PersistentFlags().StringVar(&fetchOptions.Token, "token", "", "bearer token for URL inputs, defaults to $AQ_TOKEN")
+	rootCmd.PersistentFlags().Int64Var(&fetchOptions.MaxSize, "max-size", 10<<20, "maximum size in bytes of a URL input and of decompressed data, 0 means unlimited")
+	rootCmd.PersistentFlags().BoolVar(&backupOutput, "backup-output", false, "keep a timestamped copy of an existing --output file before replacing it")
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(tomlCmd)
 }
+
+// applyUserConfig 读取用户配置文件，配置项作为对应全局参数的默认值，命令行参数优先
+func applyUserConfig() error {
+	cfg, err := pkg.LoadConfig(pkg.ConfigPath())
+	if err != nil {
+		return err
+	}
+	userConfig = cfg
+	if len(cfg.OutputFormat) > 0 {
+		outputFormat = cfg.OutputFormat
+	}
+	if len(cfg.Color) > 0 {
+		colorMode = cfg.Color
+	}
+	if len(cfg.ErrorFormat) > 0 {
+		errorFormat = cfg.ErrorFormat
+	}
+	strictMode = strictMode || cfg.Strict
+	return nil
+}
+
+// expandAlias 展开以@开头的查询别名
+func expandAlias(query string) (string, error) {
+	name, ok := strings.CutPrefix(query, "@")
+	if !ok {
+		return query, nil
+	}
+	expanded, ok := userConfig.Aliases[name]
+	if !ok {
+		return "", fmt.Errorf("unknown query alias %q, define it under [aliases] in %s", name, pkg.ConfigPath())
+	}
+	return expanded, nil
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestBadConfigOnlyWarns 配置文件有误时命令照常执行，警告写到标准错误
+func TestBadConfigOnlyWarns(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, testConfig, "output_format = \n")
+	writeFile(t, dir, "c.toml", "a = 1\n")
+
+	for _, args := range [][]string{{"version"}, {"--help"}, {"toml", "get", "a", "c.toml"}} {
+		out, stderr, code := runAq(t, dir, "", args...)
+		if code != exitOK {
+			t.Fatalf("aq %s exited %d: %s", strings.Join(args, " "), code, stderr)
+		}
+		if len(out) == 0 || strings.Contains(out, "config") {
+			t.Fatalf("aq %s stdout = %q, want the normal output", strings.Join(args, " "), out)
+		}
+		if !strings.Contains(stderr, "warning: ignoring config file") {
+			t.Fatalf("aq %s stderr = %q, want a config warning", strings.Join(args, " "), stderr)
+		}
+	}
+}
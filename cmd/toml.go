@@ -1,16 +1,27 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/dzjyyds666/aq/pkg"
 	"github.com/spf13/cobra"
 )
 
 type TomlParams struct {
-	Find   string `json:"find"`   // 查找的key
-	Input  string `json:"input"`  // 输入文件路径
-	Output string `json:"output"` // 输出文件地址
+	Find      string   `json:"find"`      // 查找的key
+	Query     string   `json:"query"`     // jq风格的查询表达式
+	Inputs    []string `json:"inputs"`    // 输入文件路径，支持glob
+	Recursive bool     `json:"recursive"` // 输入为目录时递归处理其中的文件
+	Include   []string `json:"include"`   // 递归时只处理匹配的文件
+	Exclude   []string `json:"exclude"`   // 递归时跳过匹配的文件和目录
+	Input     string   `json:"input"`     // 只有一个输入时的文件路径
+	Output    string   `json:"output"`    // 输出文件地址
+	InPlace   bool     `json:"in_place"`  // 直接修改输入文件
+	Backup    string   `json:"backup"`    // 原地修改前备份文件的后缀
 }
 
 var params *TomlParams
@@ -18,31 +29,242 @@ var params *TomlParams
 var inputStruct any // 解析到的toml之后存放在这个结构体中
 
 var tomlCmd = &cobra.Command{
-	Use:   "toml",
+	Use:   "toml [file]...",
 	Short: "toml parse tools",
-	Run:   tomlRun,
+	Args:  cobra.ArbitraryArgs,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := resolveInputs(); err != nil {
+			fail(codeUsage, exitUsage, "%s", err)
+		}
+	},
+	Run: tomlRun,
 }
 
 func init() {
 	params = &TomlParams{}
 	tomlCmd.Flags().StringVarP(&params.Find, "find", "f", "", "find")
-	tomlCmd.Flags().StringVarP(&params.Input, "input", "i", "", "input file path")
-	tomlCmd.Flags().StringVarP(&params.Output, "output", "o", "", "output path")
+	tomlCmd.Flags().StringVarP(&params.Query, "query", "q", "", "jq style query, e.g. '.servers[] | select(.enabled) | .host'")
+	addWatchFlags(tomlCmd)
+	tomlCmd.PersistentFlags().BoolVarP(&params.Recursive, "recursive", "R", false, "walk directory inputs, honoring .gitignore and .aqignore")
+	tomlCmd.PersistentFlags().StringArrayVar(&params.Include, "include", []string{"*.toml"}, "with --recursive, only process files matching this glob")
+	tomlCmd.PersistentFlags().StringArrayVar(&params.Exclude, "exclude", nil, "with --recursive, skip files and directories matching this glob")
+	tomlCmd.PersistentFlags().StringArrayVarP(&params.Inputs, "input", "i", nil, "input file path, glob or URL, repeatable; \"-\" or empty reads stdin")
+	tomlCmd.PersistentFlags().StringVarP(&params.Output, "output", "o", "", "output path")
 }
 
 func tomlRun(cmd *cobra.Command, args []string) {
-	if len(params.Input) == 0 {
-		fmt.Println("no input file path")
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	if len(params.Find) > 0 && len(params.Query) > 0 {
+		fail(codeUsage, exitUsage, "--find and --query cannot be used together")
+	}
+	var query *pkg.Query
+	if len(params.Query) > 0 {
+		expr, err := expandAlias(params.Query)
+		if err != nil {
+			fail(codeUsage, exitUsage, "%s", err)
+		}
+		q, err := pkg.CompileQuery(expr)
+		if err != nil {
+			fail(codeUsage, exitUsage, "%s", err)
+		}
+		query = q
+	}
+
+	render := func(data map[string]any) (string, error) {
+		return tomlResult(data, query)
+	}
+	if watchParams.Watch {
+		watchInput(render)
 		return
 	}
-	exist, err := pkg.CheckFileExist(params.Input)
+	runInputs(render)
+}
+
+// tomlResult 根据--query或--find计算输出，都不指定时输出整个文件
+func tomlResult(data map[string]any, query *pkg.Query) (string, error) {
+	if query != nil {
+		results, err := query.Run(data)
+		if err != nil {
+			return "", err
+		}
+		// 表格格式下所有结果合并为一个表格，其余格式每个结果单独输出一行
+		if pkg.IsTabularFormat(outputFormat) && len(results) != 1 {
+			return renderResult(results, pkg.FormatJSON), nil
+		}
+		outs := make([]string, len(results))
+		for i, result := range results {
+			outs[i] = renderResult(result, pkg.FormatJSON)
+		}
+		return strings.Join(outs, "\n"), nil
+	}
+
+	if len(params.Find) == 0 {
+		return renderResult(data, pkg.FormatRaw), nil
+	}
+	value, ok, err := pkg.FindValue(data, params.Find)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", &keyNotFoundError{paths: []string{params.Find}}
+	}
+	return renderResult(value, pkg.FormatRaw), nil
+}
+
+// loadToml 检查并解析输入文件，未指定输入或者输入为"-"时读取标准输入，同时返回key在文档中的顺序，失败时直接退出
+func loadToml() (map[string]any, pkg.KeyOrder) {
+	if len(params.Inputs) > 1 {
+		fail(codeUsage, exitUsage, "this command accepts a single input file")
+	}
+	if len(params.Input) == 0 && !pkg.IsStdinPiped() {
+		fail(codeUsage, exitUsage, "no input file path")
+	}
+	data, order := loadTomlFile(params.Input)
+	inputStruct = data
+	return data, order
+}
+
+// loadTomlFile 解析指定的文件，路径为空或者"-"时读取标准输入，同时返回key在文档中的顺序，失败时直接退出
+func loadTomlFile(file string) (map[string]any, pkg.KeyOrder) {
+	if len(file) > 0 && file != "-" && !pkg.IsURL(file) {
+		exist, err := pkg.CheckFileExist(file)
+		if err != nil {
+			printDiagnostic(fileDiagnostic(file, err), fmt.Sprint("check file exist error: ", err))
+			os.Exit(exitUsage)
+		}
+		if !exist {
+			printDiagnostic(diagnostic{File: file, Code: codeIO, Message: "input file not exist"}, "input file not exist: "+file)
+			os.Exit(exitUsage)
+		}
+	}
+
+	name, src, err := readInput(file)
+	if err != nil {
+		failRead(name, err)
+	}
+	data, order, err := pkg.DecodeTomlSourceOrdered(name, src)
 	if err != nil {
-		fmt.Println("check file exist error:", err)
+		reportParseError(name, err)
+		os.Exit(exitSyntax)
+	}
+	return data, order
+}
+
+// renderResult 按照--output-format渲染结果，未指定时使用命令自己的默认格式；指定--raw时字符串原样输出
+func renderResult(value any, defaultFormat string) string {
+	if s, ok := value.(string); ok && rawOutput {
+		return s
+	}
+	format := outputFormat
+	if len(format) == 0 {
+		format = defaultFormat
+	}
+	out, err := pkg.RenderValue(value, format)
+	if err != nil {
+		fail(codeData, exitData, "format result error: %s", err)
+	}
+	if useColor(params.Output) {
+		// raw格式下table和array输出为json
+		if format == pkg.FormatRaw && !pkg.IsScalar(value) {
+			format = pkg.FormatJSON
+		}
+		out = pkg.Colorize(out, format)
+	}
+	return out
+}
+
+// useColor 根据--color判断是否需要高亮，输出到文件（包括--in-place）时从不高亮；
+// auto时只在输出到终端且未设置NO_COLOR时高亮
+func useColor(output string) bool {
+	if len(output) > 0 || params.InPlace {
+		return false
+	}
+	switch colorMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	case "auto":
+		return len(os.Getenv("NO_COLOR")) == 0 && pkg.IsTerminal(os.Stdout)
+	}
+	fail(codeUsage, exitUsage, "unknown color mode %q, want one of auto|always|never", colorMode)
+	return false
+}
+
+// writeResult 输出结果到文件或者标准输出
+func writeResult(out string) {
+	writeOutput(params.Output, out)
+}
+
+// writeOutput 输出到指定文件，路径为空时输出到标准输出
+func writeOutput(output, out string) {
+	if len(output) > 0 {
+		writeOutputFile(output, []byte(out+"\n"))
 		return
 	}
-	if !exist {
-		fmt.Println("input file not exist")
+	if quietOutput {
 		return
 	}
+	fmt.Println(out)
+}
 
+// writeOutputFile 原子地写入--output指定的文件，开启--backup-output时先保留一份带时间戳的原文件
+func writeOutputFile(output string, data []byte) {
+	suffix := ""
+	if backupOutput {
+		suffix = pkg.TimestampSuffix(time.Now())
+	}
+	if err := pkg.WriteFileAtomic(output, data, suffix); err != nil {
+		fail(codeIO, exitUsage, "write output error: %s", err)
+	}
+}
+
+// addInPlaceFlags 为修改类命令注册原地修改相关的参数
+func addInPlaceFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&params.InPlace, "in-place", false, "rewrite the input file atomically instead of printing")
+	cmd.Flags().StringVar(&params.Backup, "backup", "", "with --in-place, keep a copy of the original with this suffix, e.g. .bak")
+}
+
+// writeDocument 修改类命令的输出，总是编码为toml，不受--output-format和--raw影响；order为nil时key按字典序输出。
+// 开启--in-place时原子替换输入文件，原文件中的注释无法保留，会给出警告
+func writeDocument(data map[string]any, order pkg.KeyOrder) {
+	if order == nil {
+		order = pkg.SortedOrder
+	}
+	out, err := pkg.EncodeTomlOrdered(data, order)
+	if err != nil {
+		fail(codeData, exitData, "encode toml error: %s", err)
+	}
+	if !params.InPlace {
+		if useColor(params.Output) {
+			out = pkg.Colorize(out, pkg.FormatTOML)
+		}
+		writeResult(out)
+		return
+	}
+	if err := checkWritable(params.Input); err != nil {
+		fail(codeUsage, exitUsage, "--in-place: %s", err)
+	}
+	if len(params.Output) > 0 {
+		fail(codeUsage, exitUsage, "--in-place cannot be used with --output")
+	}
+	if src, _ := os.ReadFile(params.Input); pkg.HasComments(src) {
+		warn(params.Input, codeCommentsDropped, "comments in %s are not preserved", params.Input)
+	}
+	if err := pkg.WriteFileAtomic(params.Input, []byte(out+"\n"), params.Backup); err != nil {
+		fail(codeIO, exitUsage, "write input file error: %s", err)
+	}
+}
+
+// checkWritable 检查输入能否原地写回：必须是本地未压缩的文件
+func checkWritable(file string) error {
+	if len(file) == 0 || file == "-" || pkg.IsURL(file) {
+		return errors.New("requires a local input file")
+	}
+	if src, err := os.ReadFile(file); err == nil && len(pkg.Compression(src)) > 0 {
+		return errors.New("does not support compressed input files")
+	}
+	return nil
 }
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlCollectParams struct {
+	As        string `json:"as"`         // 数组的名称
+	SourceKey string `json:"source_key"` // 记录来源文件名的key，为空时不记录
+}
+
+var collectParams = &TomlCollectParams{}
+
+var tomlCollectCmd = &cobra.Command{
+	Use:   "collect [file]...",
+	Short: "concatenate files into an array of tables",
+	Long:  "Build a single document where every input file becomes one element of [[<as>]], in input order, with the file name stored under --source-key.",
+	Run:   tomlCollectRun,
+}
+
+func init() {
+	tomlCollectCmd.Flags().StringVar(&collectParams.As, "as", "items", "name of the array of tables")
+	tomlCollectCmd.Flags().StringVar(&collectParams.SourceKey, "source-key", "_source", "key holding the source file name, empty disables it")
+	tomlCmd.AddCommand(tomlCollectCmd)
+}
+
+func tomlCollectRun(cmd *cobra.Command, args []string) {
+	if len(collectParams.As) == 0 {
+		fail(codeUsage, exitUsage, "--as cannot be empty")
+	}
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	files := params.Inputs
+	if len(files) == 0 {
+		files = []string{""}
+	}
+
+	items := make([]map[string]any, 0, len(files))
+	for _, file := range files {
+		item, _ := loadTomlFile(file)
+		if key := collectParams.SourceKey; len(key) > 0 {
+			if _, exist := item[key]; exist {
+				fail(codeData, exitData, "%s already has a %q key, use --source-key to pick another one", file, key)
+			}
+			item[key] = file
+			if len(file) == 0 || file == "-" {
+				item[key] = "<stdin>"
+			}
+		}
+		items = append(items, item)
+	}
+	writeResult(renderResult(map[string]any{collectParams.As: items}, pkg.FormatTOML))
+}
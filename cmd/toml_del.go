@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlDelParams struct {
+	PruneEmpty bool `json:"prune_empty"` // 删除后变空的表也一并删除
+}
+
+var delParams = &TomlDelParams{}
+
+var tomlDelCmd = &cobra.Command{
+	Use:               "del <path>... [file]",
+	Short:             "delete keys, tables or array elements by path and print the resulting toml",
+	Args:              inputArgs(cobra.MinimumNArgs(1)),
+	ValidArgsFunction: completeKeyPaths,
+	Run:               tomlDelRun,
+}
+
+func init() {
+	tomlDelCmd.Flags().BoolVar(&delParams.PruneEmpty, "prune-empty", false, "drop tables that become empty")
+	addInPlaceFlags(tomlDelCmd)
+	tomlCmd.AddCommand(tomlDelCmd)
+}
+
+func tomlDelRun(cmd *cobra.Command, args []string) {
+	args, files := splitInputArgs(args)
+	if err := resolveInputs(files...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	data, order := loadToml()
+	for _, path := range args {
+		keys, err := pkg.ParsePath(path)
+		if err != nil {
+			fail(codeUsage, exitUsage, "%s", err)
+		}
+		// key不存在时跳过，方便批量清理多个文件
+		found, err := pkg.DeleteValue(data, keys, delParams.PruneEmpty)
+		if err != nil {
+			fail(codeData, exitData, "delete value error: %s", err)
+		}
+		if !found {
+			warn(params.Input, codeMissingKey, "key not found, skipped: %s", path)
+		}
+	}
+
+	writeDocument(data, order)
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlDiffParams struct {
+	Format string `json:"format"` // 差异的输出格式
+}
+
+var diffParams = &TomlDiffParams{}
+
+var tomlDiffCmd = &cobra.Command{
+	Use:   "diff <a.toml> <b.toml>",
+	Short: "compare two toml documents by key path",
+	Long:  "Compare two documents semantically, ignoring formatting and key order. Exits 1 when differences exist.",
+	Args:  cobra.ExactArgs(2),
+	Run:   tomlDiffRun,
+}
+
+func init() {
+	tomlDiffCmd.Flags().StringVar(&diffParams.Format, "format", "unified", "diff format: unified|json|table")
+	tomlCmd.AddCommand(tomlDiffCmd)
+}
+
+func tomlDiffRun(cmd *cobra.Command, args []string) {
+	a, _ := loadTomlFile(args[0])
+	b, _ := loadTomlFile(args[1])
+	changes := pkg.Diff(a, b)
+
+	out, err := formatChanges(changes, diffParams.Format)
+	if err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	if len(out) > 0 {
+		writeResult(out)
+	}
+	if len(changes) > 0 {
+		os.Exit(exitData)
+	}
+}
+
+// formatChanges 按照指定格式输出差异
+func formatChanges(changes []pkg.Change, format string) (string, error) {
+	switch format {
+	case "unified":
+		var sb strings.Builder
+		for _, c := range changes {
+			if c.Type != pkg.ChangeAdded {
+				fmt.Fprintf(&sb, "- %s = %s\n", c.Path, pkg.InlineValue(c.Old))
+			}
+			if c.Type != pkg.ChangeRemoved {
+				fmt.Fprintf(&sb, "+ %s = %s\n", c.Path, pkg.InlineValue(c.New))
+			}
+		}
+		return strings.TrimSuffix(sb.String(), "\n"), nil
+	case "json":
+		if changes == nil {
+			changes = []pkg.Change{}
+		}
+		for i := range changes {
+			changes[i].Old = pkg.NormalizeValue(changes[i].Old)
+			changes[i].New = pkg.NormalizeValue(changes[i].New)
+		}
+		raw, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(raw), nil
+	case "table":
+		if len(changes) == 0 {
+			return "", nil
+		}
+		var sb strings.Builder
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PATH\tCHANGE\tOLD\tNEW")
+		for _, c := range changes {
+			oldText, newText := "", ""
+			if c.Type != pkg.ChangeAdded {
+				oldText = pkg.InlineValue(c.Old)
+			}
+			if c.Type != pkg.ChangeRemoved {
+				newText = pkg.InlineValue(c.New)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Path, c.Type, oldText, newText)
+		}
+		w.Flush()
+		return strings.TrimSuffix(sb.String(), "\n"), nil
+	}
+	return "", fmt.Errorf("unknown diff format %q, want one of unified|json|table", format)
+}
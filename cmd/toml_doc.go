@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlDocParams struct {
+	Schema string `json:"schema"` // 根据schema生成文档
+	Title  string `json:"title"`  // 文档的标题
+}
+
+var docParams = &TomlDocParams{}
+
+var tomlDocCmd = &cobra.Command{
+	Use:   "doc [file]",
+	Short: "generate Markdown documentation of a config",
+	Long:  "Print a Markdown reference with one section per table listing key paths, types, example values and descriptions. Descriptions come from the comment above or after each key, or from description/default/examples with --schema.",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   tomlDocRun,
+}
+
+func init() {
+	tomlDocCmd.Flags().StringVarP(&docParams.Schema, "schema", "s", "", "document this schema (json or toml) instead of a config file")
+	tomlDocCmd.Flags().StringVar(&docParams.Title, "title", "", "title of the document")
+	tomlCmd.AddCommand(tomlDocCmd)
+}
+
+func tomlDocRun(cmd *cobra.Command, args []string) {
+	if len(docParams.Schema) > 0 {
+		schema, err := loadSchema(docParams.Schema)
+		if err != nil {
+			failFile(docParams.Schema, err, exitUsage, "load schema error")
+		}
+		title := docParams.Title
+		if len(title) == 0 {
+			title = schema.Title
+		}
+		writeResult(pkg.RenderDoc(title, pkg.DocFromSchema(schema), true))
+		return
+	}
+
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	if len(params.Inputs) > 1 {
+		fail(codeUsage, exitUsage, "this command accepts a single input file")
+	}
+	name, src, err := readInput(params.Input)
+	if err != nil {
+		failRead(name, err)
+	}
+	data, err := pkg.DecodeTomlSource(name, src)
+	if err != nil {
+		reportParseError(name, err)
+		os.Exit(exitSyntax)
+	}
+	writeResult(pkg.RenderDoc(docParams.Title, pkg.DocFromData(data, src), false))
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlEnvParams struct {
+	Prefix    string `json:"prefix"`    // 变量名前缀
+	Separator string `json:"separator"` // 路径各段之间的分隔符
+	Case      string `json:"case"`      // 变量名大小写
+	Quote     string `json:"quote"`     // 值的引号风格
+	Export    bool   `json:"export"`    // 每行前面加上export
+}
+
+var envParams = &TomlEnvParams{}
+
+var tomlEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "export the document as environment variables",
+	Long:  "Flatten the document into NAME=value lines, e.g. [server] port = 8080 becomes SERVER_PORT=8080, suitable for eval or a .env file.",
+	Args:  cobra.NoArgs,
+	Run:   tomlEnvRun,
+}
+
+func init() {
+	tomlEnvCmd.Flags().StringVar(&envParams.Prefix, "prefix", "", "prefix prepended to every variable name, e.g. APP_")
+	tomlEnvCmd.Flags().StringVar(&envParams.Separator, "separator", "_", "separator between path segments")
+	tomlEnvCmd.Flags().StringVar(&envParams.Case, "case", "upper", "variable name case: upper|lower|keep")
+	tomlEnvCmd.Flags().StringVar(&envParams.Quote, "quote", "auto", "value quoting: auto|single|double|none, auto quotes only when needed")
+	tomlEnvCmd.Flags().BoolVar(&envParams.Export, "export", false, "prefix each line with export")
+	tomlCmd.AddCommand(tomlEnvCmd)
+}
+
+func tomlEnvRun(cmd *cobra.Command, args []string) {
+	switch envParams.Case {
+	case "upper", "lower", "keep":
+	default:
+		fail(codeUsage, exitUsage, "unknown case %q, want one of upper|lower|keep", envParams.Case)
+	}
+	switch envParams.Quote {
+	case "auto", "single", "double", "none":
+	default:
+		fail(codeUsage, exitUsage, "unknown quote style %q, want one of auto|single|double|none", envParams.Quote)
+	}
+
+	data, _ := loadToml()
+	var lines []string
+	names := map[string]string{} // 变量名 -> 生成该变量的key路径
+	for _, entry := range pkg.Flatten(data) {
+		path := pkg.FormatPath(entry.Path)
+		// 空的table和array无法表示为环境变量
+		if !pkg.IsScalar(entry.Value) {
+			warn(params.Input, codeSkipped, "empty %s cannot be exported, skipped: %s", pkg.TypeName(entry.Value), path)
+			continue
+		}
+		name := envName(entry.Path)
+		if other, ok := names[name]; ok {
+			warn(params.Input, codeEnvConflict, "%s and %s both map to %s", other, path, name)
+		}
+		names[name] = path
+		line := name + "=" + envQuote(envValue(entry.Value))
+		if envParams.Export {
+			line = "export " + line
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > 0 {
+		writeResult(strings.Join(lines, "\n"))
+	}
+}
+
+// envName 由路径生成变量名，非字母数字的字符替换为下划线
+func envName(path []pkg.PathKey) string {
+	parts := make([]string, len(path))
+	for i, k := range path {
+		if k.IsIndex {
+			parts[i] = strconv.Itoa(k.Index)
+			continue
+		}
+		parts[i] = strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+				return r
+			}
+			return '_'
+		}, k.Key)
+	}
+	name := envParams.Prefix + strings.Join(parts, envParams.Separator)
+	switch envParams.Case {
+	case "upper":
+		return strings.ToUpper(name)
+	case "lower":
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// envValue 标量转换为文本
+func envValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return pkg.InlineValue(value)
+}
+
+// envQuote 按照引号风格转义值
+func envQuote(value string) string {
+	quote := envParams.Quote
+	if quote == "auto" {
+		quote = "none"
+		for _, r := range value {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-./:@+,", r)) {
+				quote = "single"
+				break
+			}
+		}
+	}
+	switch quote {
+	case "single":
+		return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	case "double":
+		r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`", "\n", `\n`)
+		return `"` + r.Replace(value) + `"`
+	}
+	return value
+}
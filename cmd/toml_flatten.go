@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+var tomlFlattenCmd = &cobra.Command{
+	Use:   "flatten",
+	Short: "print the document as dotted key = value lines",
+	Args:  cobra.NoArgs,
+	Run:   tomlFlattenRun,
+}
+
+var tomlUnflattenCmd = &cobra.Command{
+	Use:   "unflatten",
+	Short: "rebuild a toml document from dotted key = value lines",
+	Args:  cobra.NoArgs,
+	Run:   tomlUnflattenRun,
+}
+
+func init() {
+	tomlCmd.AddCommand(tomlFlattenCmd)
+	tomlCmd.AddCommand(tomlUnflattenCmd)
+}
+
+func tomlFlattenRun(cmd *cobra.Command, args []string) {
+	data, _ := loadToml()
+	var lines []string
+	for _, entry := range pkg.Flatten(data) {
+		literal, err := pkg.TomlLiteral(entry.Value)
+		if err != nil {
+			fail(codeData, exitData, "format value error: %s", err)
+		}
+		lines = append(lines, pkg.FormatPath(entry.Path)+" = "+literal)
+	}
+	if len(lines) > 0 {
+		writeResult(strings.Join(lines, "\n"))
+	}
+}
+
+func tomlUnflattenRun(cmd *cobra.Command, args []string) {
+	name, src, err := readInput(params.Input)
+	if err != nil {
+		failRead(name, err)
+	}
+
+	data, err := pkg.Unflatten(bytes.NewReader(src))
+	if err != nil {
+		fail(codeSyntax, exitSyntax, "unflatten %s error: %v", name, err)
+	}
+	writeResult(renderResult(data, pkg.FormatTOML))
+}
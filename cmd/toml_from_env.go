@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlFromEnvParams struct {
+	Prefix    string `json:"prefix"`    // 只读取带有该前缀的变量
+	Separator string `json:"separator"` // 路径各段之间的分隔符
+	Case      string `json:"case"`      // key的大小写
+	Infer     bool   `json:"infer"`     // 按toml字面量推断值的类型
+}
+
+var fromEnvParams = &TomlFromEnvParams{}
+
+var tomlFromEnvCmd = &cobra.Command{
+	Use:   "from-env --prefix APP_",
+	Short: "build a toml document from environment variables",
+	Long:  "The inverse of env: every variable starting with --prefix becomes a key, e.g. APP_SERVER_PORT=8080 becomes [server] port = 8080. Numeric segments are array indexes and values are typed like toml literals unless --infer=false.",
+	Args:  cobra.NoArgs,
+	Run:   tomlFromEnvRun,
+}
+
+func init() {
+	tomlFromEnvCmd.Flags().StringVar(&fromEnvParams.Prefix, "prefix", "", "only read variables starting with this prefix, which is removed from the key")
+	tomlFromEnvCmd.Flags().StringVar(&fromEnvParams.Separator, "separator", "_", "separator between path segments, e.g. __ to keep single underscores in keys")
+	tomlFromEnvCmd.Flags().StringVar(&fromEnvParams.Case, "case", "lower", "key case: lower|keep")
+	tomlFromEnvCmd.Flags().BoolVar(&fromEnvParams.Infer, "infer", true, "infer integer, float, boolean and datetime values, otherwise every value is a string")
+	tomlFromEnvCmd.MarkFlagRequired("prefix")
+	tomlCmd.AddCommand(tomlFromEnvCmd)
+}
+
+// envEntry 一个匹配前缀的环境变量
+type envEntry struct {
+	name  string
+	path  []pkg.PathKey
+	value string
+}
+
+func tomlFromEnvRun(cmd *cobra.Command, args []string) {
+	switch fromEnvParams.Case {
+	case "lower", "keep":
+	default:
+		fail(codeUsage, exitUsage, "unknown case %q, want one of lower|keep", fromEnvParams.Case)
+	}
+	if len(fromEnvParams.Separator) == 0 {
+		fail(codeUsage, exitUsage, "--separator cannot be empty")
+	}
+
+	var entries []envEntry
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, fromEnvParams.Prefix) {
+			continue
+		}
+		path, err := envPath(strings.TrimPrefix(name, fromEnvParams.Prefix))
+		if err != nil {
+			warn("<env>", codeSkipped, "%s: %s, skipped", name, err)
+			continue
+		}
+		entries = append(entries, envEntry{name: name, path: path, value: value})
+	}
+	// 数组下标按数值排序，保证元素按顺序追加
+	sort.Slice(entries, func(i, j int) bool {
+		return envPathLess(entries[i].path, entries[j].path)
+	})
+
+	data := map[string]any{}
+	for _, e := range entries {
+		var value any = e.value
+		if fromEnvParams.Infer {
+			value, _ = pkg.ParseLiteral(e.value, "")
+		}
+		if err := pkg.SetValue(data, e.path, value); err != nil {
+			warn("<env>", codeEnvConflict, "%s: %s, skipped", e.name, err)
+		}
+	}
+	writeResult(renderResult(data, pkg.FormatTOML))
+}
+
+// envPath 将去掉前缀的变量名按分隔符拆分为路径，纯数字的段作为数组下标
+func envPath(name string) ([]pkg.PathKey, error) {
+	if fromEnvParams.Case == "lower" {
+		name = strings.ToLower(name)
+	}
+	var path []pkg.PathKey
+	for _, part := range strings.Split(name, fromEnvParams.Separator) {
+		if len(part) == 0 {
+			return nil, fmt.Errorf("empty key segment")
+		}
+		if index, err := strconv.Atoi(part); err == nil && len(path) > 0 {
+			path = append(path, pkg.PathKey{Index: index, IsIndex: true})
+			continue
+		}
+		path = append(path, pkg.PathKey{Key: part})
+	}
+	return path, nil
+}
+
+// envPathLess 逐段比较路径，下标按数值比较
+func envPathLess(a, b []pkg.PathKey) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i].IsIndex && b[i].IsIndex {
+			if a[i].Index != b[i].Index {
+				return a[i].Index < b[i].Index
+			}
+			continue
+		}
+		if a[i].IsIndex != b[i].IsIndex {
+			return a[i].IsIndex
+		}
+		if a[i].Key != b[i].Key {
+			return a[i].Key < b[i].Key
+		}
+	}
+	return len(a) < len(b)
+}
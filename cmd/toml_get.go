@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlGetParams struct {
+	Default string `json:"default"` // key不存在时输出的默认值
+}
+
+var getParams = &TomlGetParams{}
+
+var tomlGetCmd = &cobra.Command{
+	Use:               "get <path>... [file]...",
+	Short:             "get values by path, e.g. servers[1].host",
+	Args:              inputArgs(cobra.MinimumNArgs(1)),
+	ValidArgsFunction: completeKeyPaths,
+	Run:               tomlGetRun,
+}
+
+func init() {
+	tomlGetCmd.Flags().StringVarP(&getParams.Default, "default", "d", "", "value printed when the key is missing")
+	addWatchFlags(tomlGetCmd)
+	tomlCmd.AddCommand(tomlGetCmd)
+}
+
+func tomlGetRun(cmd *cobra.Command, args []string) {
+	hasDefault := cmd.Flags().Changed("default")
+	args, files := splitInputArgs(args)
+	if err := resolveInputs(files...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	for _, path := range args {
+		if _, err := pkg.ParsePath(path); err != nil {
+			fail(codeUsage, exitUsage, "%s", err)
+		}
+	}
+
+	render := func(data map[string]any) (string, error) {
+		return getResults(data, args, hasDefault)
+	}
+	if watchParams.Watch {
+		watchInput(render)
+		return
+	}
+	runInputs(render)
+}
+
+// getResults 依次查找每个路径，找不到的路径在返回的错误中列出
+func getResults(data map[string]any, paths []string, hasDefault bool) (string, error) {
+	var results, missing []string
+	for _, path := range paths {
+		value, ok, err := pkg.FindValue(data, path)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			if hasDefault {
+				results = append(results, getParams.Default)
+			} else {
+				missing = append(missing, path)
+			}
+			continue
+		}
+		results = append(results, renderResult(value, pkg.FormatRaw))
+	}
+
+	out := strings.Join(results, "\n")
+	if len(missing) > 0 {
+		return out, &keyNotFoundError{paths: missing}
+	}
+	return out, nil
+}
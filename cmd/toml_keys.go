@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlKeysParams struct {
+	Types    bool   `json:"types"`     // 输出值的类型
+	Values   bool   `json:"values"`    // 输出标量的值
+	MaxDepth int    `json:"max_depth"` // 最大深度，0表示不限制
+	Prefix   string `json:"prefix"`    // 只输出以此开头的路径
+}
+
+var keysParams = &TomlKeysParams{}
+
+var tomlKeysCmd = &cobra.Command{
+	Use:   "keys [file]...",
+	Short: "list every key path in the document",
+	Run:   tomlKeysRun,
+}
+
+func init() {
+	tomlKeysCmd.Flags().BoolVarP(&keysParams.Types, "types", "t", false, "print the type of each value")
+	tomlKeysCmd.Flags().BoolVarP(&keysParams.Values, "values", "v", false, "print scalar values")
+	tomlKeysCmd.Flags().IntVar(&keysParams.MaxDepth, "max-depth", 0, "limit the path depth, 0 means unlimited")
+	tomlKeysCmd.Flags().StringVar(&keysParams.Prefix, "prefix", "", "only print paths starting with this prefix")
+	tomlCmd.AddCommand(tomlKeysCmd)
+}
+
+func tomlKeysRun(cmd *cobra.Command, args []string) {
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	runInputs(keyLines)
+}
+
+// keyLines 列出文档中的key路径
+func keyLines(data map[string]any) (string, error) {
+	var lines []string
+	pkg.Walk(data, func(path []pkg.PathKey, value any) bool {
+		p := pkg.FormatPath(path)
+		if strings.HasPrefix(p, keysParams.Prefix) {
+			line := p
+			if keysParams.Types {
+				line += " (" + pkg.TypeName(value) + ")"
+			}
+			if keysParams.Values && pkg.IsScalar(value) {
+				line += " = " + pkg.InlineValue(value)
+			}
+			lines = append(lines, line)
+		}
+		return keysParams.MaxDepth <= 0 || len(path) < keysParams.MaxDepth
+	})
+	return strings.Join(lines, "\n"), nil
+}
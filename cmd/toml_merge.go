@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlMergeParams struct {
+	Arrays string `json:"arrays"` // 数组的合并策略
+}
+
+var mergeParams = &TomlMergeParams{}
+
+var tomlMergeCmd = &cobra.Command{
+	Use:   "merge <base.toml> <override.toml>...",
+	Short: "merge toml files, later files override earlier ones",
+	Args:  cobra.MinimumNArgs(2),
+	Run:   tomlMergeRun,
+}
+
+func init() {
+	tomlMergeCmd.Flags().StringVar(&mergeParams.Arrays, "arrays", pkg.ArraysReplace, "array strategy: append|replace")
+	tomlCmd.AddCommand(tomlMergeCmd)
+}
+
+func tomlMergeRun(cmd *cobra.Command, args []string) {
+	merged, _ := loadTomlFile(args[0])
+	for _, file := range args[1:] {
+		next, _ := loadTomlFile(file)
+		if err := pkg.Merge(merged, next, mergeParams.Arrays); err != nil {
+			fail(codeData, exitData, "%s", err)
+		}
+	}
+	writeResult(renderResult(merged, pkg.FormatTOML))
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlSchemaParams struct {
+	MaxEnum int    `json:"max_enum"` // 字符串取值不超过该数量时输出enum
+	Format  string `json:"format"`   // schema文件的格式
+	Schema  string `json:"schema"`   // check使用的schema文件
+	Title   string `json:"title"`    // schema的title
+}
+
+var schemaParams = &TomlSchemaParams{}
+
+var tomlSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "infer and check schemas of toml documents",
+}
+
+var tomlSchemaInferCmd = &cobra.Command{
+	Use:     "infer [file]...",
+	Aliases: []string{"to-jsonschema"},
+	Short:   "infer a JSON Schema from one or more example documents",
+	Long:    "Infer types, optionality and (with --max-enum) observed enum values from example documents, merging them when several are given. Keys missing from any example are optional.",
+	Run:     tomlSchemaInferRun,
+}
+
+var tomlSchemaCheckCmd = &cobra.Command{
+	Use:   "check --schema schema.json [file]...",
+	Short: "validate toml documents against a JSON Schema",
+	Long:  "Print every violation as file:line: path: message (rule). Exits 0 when all files conform, 1 on violations, 2 on syntax errors and 3 on IO errors.",
+	Run:   tomlSchemaCheckRun,
+}
+
+func init() {
+	tomlSchemaCheckCmd.Flags().StringVarP(&schemaParams.Schema, "schema", "s", "", "schema file, json or toml by extension")
+	tomlSchemaCheckCmd.MarkFlagRequired("schema")
+	tomlSchemaCmd.AddCommand(tomlSchemaCheckCmd)
+	tomlSchemaInferCmd.Flags().IntVar(&schemaParams.MaxEnum, "max-enum", 0, "emit enum for string fields with at most this many distinct values, 0 disables")
+	tomlSchemaInferCmd.Flags().StringVar(&schemaParams.Format, "format", pkg.FormatJSON, "schema file format: json|toml")
+	tomlSchemaInferCmd.Flags().StringVar(&schemaParams.Title, "title", "", "title of the schema")
+	tomlSchemaCmd.AddCommand(tomlSchemaInferCmd)
+	tomlCmd.AddCommand(tomlSchemaCmd)
+}
+
+func tomlSchemaInferRun(cmd *cobra.Command, args []string) {
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	files := params.Inputs
+	if len(files) == 0 {
+		files = []string{""}
+	}
+
+	inferrer := &pkg.SchemaInferrer{MaxEnum: schemaParams.MaxEnum}
+	for _, file := range files {
+		data, _ := loadTomlFile(file)
+		inferrer.Observe(data)
+	}
+
+	schema := inferrer.Schema()
+	schema.Title = schemaParams.Title
+	out, err := renderSchema(schema, schemaParams.Format)
+	if err != nil {
+		fail(codeData, exitData, "format schema error: %s", err)
+	}
+	writeResult(out)
+}
+
+func tomlSchemaCheckRun(cmd *cobra.Command, args []string) {
+	schema, err := loadSchema(schemaParams.Schema)
+	if err != nil {
+		failFile(schemaParams.Schema, err, exitUsage, "load schema error")
+	}
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	files := params.Inputs
+	if len(files) == 0 {
+		files = []string{""}
+	}
+
+	os.Exit(eachFile(files, func(file string) func() int {
+		return checkSchemaFile(schema, file)
+	}))
+}
+
+// checkSchemaFile 按schema校验单个文件，返回输出违反项并给出退出码的函数
+func checkSchemaFile(schema *pkg.Schema, file string) func() int {
+	name, src, err := readInput(file)
+	if err != nil {
+		return func() int {
+			reportFileError(name, err)
+			return exitUsage
+		}
+	}
+	data, err := pkg.DecodeTomlSource(name, src)
+	if err != nil {
+		return func() int {
+			reportFileError(name, err)
+			return exitCode(err)
+		}
+	}
+
+	violations := pkg.ValidateSchema(schema, data)
+	if len(violations) == 0 {
+		return func() int { return exitOK }
+	}
+	lines := pkg.LocateKeys(src)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return lines.Line(violations[i].Path) < lines.Line(violations[j].Path)
+	})
+	return func() int {
+		if !quietOutput {
+			printViolations(name, lines, violations)
+		}
+		return exitData
+	}
+}
+
+// printViolations 按行号输出文件中的违反项
+func printViolations(name string, lines pkg.KeyLines, violations []pkg.Violation) {
+	for _, v := range violations {
+		path := pkg.FormatPath(v.Path)
+		if len(path) == 0 {
+			path = "."
+		}
+		line := lines.Line(v.Path)
+		location := name
+		if line > 0 {
+			location = fmt.Sprintf("%s:%d", name, line)
+		}
+		d := diagnostic{File: name, Line: line, Code: codeSchema + "/" + v.Rule, Message: path + ": " + v.Message}
+		printDiagnostic(d, fmt.Sprintf("%s: %s: %s (%s)", location, path, v.Message, v.Rule))
+	}
+}
+
+// loadSchema 读取json或toml格式的schema文件
+func loadSchema(file string) (*pkg.Schema, error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	format := pkg.DetectFormat(file)
+	if format != pkg.FormatTOML {
+		format = pkg.FormatJSON
+	}
+	docs, err := pkg.DecodeSource(file, src, format)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(pkg.NormalizeValue(docs[0]))
+	if err != nil {
+		return nil, err
+	}
+	schema := &pkg.Schema{}
+	if err := json.Unmarshal(raw, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// renderSchema 以json或者toml输出schema
+func renderSchema(schema *pkg.Schema, format string) (string, error) {
+	raw, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case pkg.FormatJSON:
+		return string(raw), nil
+	case pkg.FormatTOML:
+		var data map[string]any
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return "", err
+		}
+		return pkg.EncodeToml(data)
+	}
+	return "", fmt.Errorf("unknown schema format %q, want one of json|toml", format)
+}
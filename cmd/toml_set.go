@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlSetParams struct {
+	Type string `json:"type"` // 强制指定值的类型
+}
+
+var setParams = &TomlSetParams{}
+
+var tomlSetCmd = &cobra.Command{
+	Use:               "set <path> <value> [file]",
+	Short:             "set a value by path and print the resulting toml",
+	Args:              inputArgs(cobra.ExactArgs(2)),
+	ValidArgsFunction: completeFirstKeyPath,
+	Run:               tomlSetRun,
+}
+
+func init() {
+	tomlSetCmd.Flags().StringVarP(&setParams.Type, "type", "t", "", "value type: string|int|float|bool|datetime (inferred when empty)")
+	addInPlaceFlags(tomlSetCmd)
+	tomlCmd.AddCommand(tomlSetCmd)
+}
+
+func tomlSetRun(cmd *cobra.Command, args []string) {
+	args, files := splitInputArgs(args)
+	if err := resolveInputs(files...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	keys, err := pkg.ParsePath(args[0])
+	if err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	value, err := pkg.ParseLiteral(args[1], setParams.Type)
+	if err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+
+	data, order := loadToml()
+	if err := pkg.SetValue(data, keys, value); err != nil {
+		fail(codeData, exitData, "set value error: %s", err)
+	}
+
+	writeDocument(data, order)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"slices"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlSortParams struct {
+	Tables []string `json:"tables"` // 只对这些表（及其子表）排序
+}
+
+var sortParams = &TomlSortParams{}
+
+var tomlSortCmd = &cobra.Command{
+	Use:   "sort",
+	Short: "rewrite the document with tables and keys sorted",
+	Long:  "Rewrite the document with tables and keys sorted lexicographically. With --table only the chosen tables (and their sub-tables) are sorted, everything else keeps the document order.",
+	Args:  cobra.NoArgs,
+	Run:   tomlSortRun,
+}
+
+func init() {
+	tomlSortCmd.Flags().StringArrayVarP(&sortParams.Tables, "table", "t", nil, "only sort within this table, repeatable")
+	addInPlaceFlags(tomlSortCmd)
+	tomlCmd.AddCommand(tomlSortCmd)
+}
+
+func tomlSortRun(cmd *cobra.Command, args []string) {
+	data, docOrder := loadToml()
+
+	var tables [][]string
+	for _, t := range sortParams.Tables {
+		keys, err := pkg.ParsePath(t)
+		if err != nil {
+			fail(codeUsage, exitUsage, "%s", err)
+		}
+		var path []string
+		for _, k := range keys {
+			if k.IsIndex {
+				fail(codeUsage, exitUsage, "--table does not accept array indexes: %s", t)
+			}
+			path = append(path, k.Key)
+		}
+		tables = append(tables, path)
+	}
+
+	order := pkg.SortedOrder
+	if len(tables) > 0 {
+		order = func(path []string, table map[string]any) []string {
+			for _, t := range tables {
+				if len(path) >= len(t) && slices.Equal(path[:len(t)], t) {
+					return pkg.SortedOrder(path, table)
+				}
+			}
+			return docOrder(path, table)
+		}
+	}
+
+	writeDocument(data, order)
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlSplitParams struct {
+	Dir   string `json:"dir"`   // 拆分后文件所在的目录
+	Force bool   `json:"force"` // 覆盖已存在的文件
+}
+
+var splitParams = &TomlSplitParams{}
+
+var tomlSplitCmd = &cobra.Command{
+	Use:   "split --dir <dir> [file]",
+	Short: "write each top-level table to its own file",
+	Long:  "Write every top-level table to <dir>/<table>.toml; keys that are not tables go to <dir>/" + pkg.SplitRest + ".toml. Key order is kept. Existing files are only replaced with --force.",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   tomlSplitRun,
+}
+
+var tomlJoinCmd = &cobra.Command{
+	Use:   "join <dir|file>...",
+	Short: "join files written by split back into one document",
+	Long:  "Read every .toml file in the given directories (or the given files) and nest each one under a top-level table named after the file; " + pkg.SplitRest + ".toml is placed at the top level.",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   tomlJoinRun,
+}
+
+func init() {
+	tomlSplitCmd.Flags().StringVarP(&splitParams.Dir, "dir", "d", "", "directory to write the files to, created if missing")
+	tomlSplitCmd.Flags().BoolVar(&splitParams.Force, "force", false, "replace existing files")
+	tomlSplitCmd.MarkFlagRequired("dir")
+	tomlCmd.AddCommand(tomlSplitCmd)
+	tomlCmd.AddCommand(tomlJoinCmd)
+}
+
+func tomlSplitRun(cmd *cobra.Command, args []string) {
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	data, order := loadToml()
+	docs, err := pkg.SplitTables(data)
+	if err != nil {
+		fail(codeData, exitData, "%s", err)
+	}
+
+	// 先输出顶层的key，其余按表在文档中的顺序
+	names := []string{}
+	if _, ok := docs[pkg.SplitRest]; ok {
+		names = append(names, pkg.SplitRest)
+	}
+	for _, k := range order(nil, data) {
+		if _, ok := docs[k]; ok && k != pkg.SplitRest {
+			names = append(names, k)
+		}
+	}
+
+	files := make([]string, len(names))
+	for i, name := range names {
+		files[i] = filepath.Join(splitParams.Dir, name+".toml")
+		if exist, _ := pkg.CheckFileExist(files[i]); exist && !splitParams.Force {
+			fail(codeUsage, exitUsage, "%s already exists, use --force to replace it", files[i])
+		}
+	}
+	if err := os.MkdirAll(splitParams.Dir, 0o755); err != nil {
+		fail(codeIO, exitUsage, "create directory error: %s", err)
+	}
+
+	for i, name := range names {
+		prefix := []string{name}
+		if name == pkg.SplitRest {
+			prefix = nil
+		}
+		out, err := pkg.EncodeTomlOrdered(docs[name], func(path []string, table map[string]any) []string {
+			return order(append(append([]string(nil), prefix...), path...), table)
+		})
+		if err != nil {
+			fail(codeData, exitData, "encode toml error: %s", err)
+		}
+		if err := pkg.WriteFileAtomic(files[i], []byte(out+"\n"), ""); err != nil {
+			fail(codeIO, exitUsage, "write file error: %s", err)
+		}
+		if !quietOutput {
+			fmt.Println(files[i])
+		}
+	}
+}
+
+func tomlJoinRun(cmd *cobra.Command, args []string) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			fail(codeUsage, exitUsage, "%s", err)
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(arg, "*.toml"))
+		if len(matches) == 0 {
+			warn(arg, codeEmptyDir, "no .toml files in %s", arg)
+		}
+		files = append(files, matches...)
+	}
+
+	docs := map[string]map[string]any{}
+	orders := map[string]pkg.KeyOrder{}
+	var names []string
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if _, exist := docs[name]; exist {
+			fail(codeUsage, exitUsage, "more than one file is named %s", filepath.Base(file))
+		}
+		docs[name], orders[name] = loadTomlFile(file)
+		names = append(names, name)
+	}
+	data, err := pkg.JoinTables(docs)
+	if err != nil {
+		fail(codeData, exitData, "%s", err)
+	}
+
+	// 顶层先输出_root中的key，再按文件的顺序输出各个表，表内保持原文件中的顺序
+	out, err := pkg.EncodeTomlOrdered(data, func(path []string, table map[string]any) []string {
+		if len(path) == 0 {
+			var keys []string
+			if rest, ok := docs[pkg.SplitRest]; ok {
+				keys = orders[pkg.SplitRest](nil, rest)
+			}
+			for _, name := range names {
+				if name != pkg.SplitRest {
+					keys = append(keys, name)
+				}
+			}
+			return keys
+		}
+		if order, ok := orders[path[0]]; ok && path[0] != pkg.SplitRest {
+			return order(path[1:], table)
+		}
+		return orders[pkg.SplitRest](path, table)
+	})
+	if err != nil {
+		fail(codeData, exitData, "encode toml error: %s", err)
+	}
+	writeResult(out)
+}
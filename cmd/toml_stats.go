@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlStatsParams struct {
+	Top int `json:"top"` // 输出最大的数组个数
+}
+
+var statsParams = &TomlStatsParams{}
+
+var tomlStatsCmd = &cobra.Command{
+	Use:   "stats [file]...",
+	Short: "print statistics about the document",
+	Long:  "Count tables, keys and arrays, and report the maximum nesting depth, a histogram of value types, the largest arrays and the total size of string values.",
+	Run:   tomlStatsRun,
+}
+
+func init() {
+	tomlStatsCmd.Flags().IntVar(&statsParams.Top, "top", 5, "number of largest arrays to print, 0 prints all")
+	tomlCmd.AddCommand(tomlStatsCmd)
+}
+
+func tomlStatsRun(cmd *cobra.Command, args []string) {
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	runInputs(statsReport)
+}
+
+// statsReport 默认输出对齐的文本，指定--output-format时按对应格式输出
+func statsReport(data map[string]any) (string, error) {
+	stats := pkg.CollectStats(data, statsParams.Top)
+	if len(outputFormat) > 0 {
+		return renderResult(statsValue(stats), pkg.FormatJSON), nil
+	}
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "tables\t%d\n", stats.Tables)
+	fmt.Fprintf(w, "keys\t%d\n", stats.Keys)
+	fmt.Fprintf(w, "arrays\t%d\n", stats.Arrays)
+	fmt.Fprintf(w, "max depth\t%d\n", stats.MaxDepth)
+	fmt.Fprintf(w, "string bytes\t%d\n", stats.StringBytes)
+	if len(stats.Types) > 0 {
+		fmt.Fprintln(w, "types:")
+		for _, typ := range sortedCounts(stats.Types) {
+			fmt.Fprintf(w, "  %s\t%d\n", typ, stats.Types[typ])
+		}
+	}
+	if len(stats.LargestArrays) > 0 {
+		fmt.Fprintln(w, "largest arrays:")
+		for _, arr := range stats.LargestArrays {
+			fmt.Fprintf(w, "  %s\t%d\n", pkg.FormatPath(arr.Path), arr.Length)
+		}
+	}
+	w.Flush()
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+// statsValue 将统计信息转换为可以按json、yaml或toml输出的值
+func statsValue(stats *pkg.Stats) map[string]any {
+	types := map[string]any{}
+	for typ, n := range stats.Types {
+		types[typ] = int64(n)
+	}
+	arrays := []any{}
+	for _, arr := range stats.LargestArrays {
+		arrays = append(arrays, map[string]any{"path": pkg.FormatPath(arr.Path), "length": int64(arr.Length)})
+	}
+	return map[string]any{
+		"tables":         int64(stats.Tables),
+		"keys":           int64(stats.Keys),
+		"arrays":         int64(stats.Arrays),
+		"max_depth":      int64(stats.MaxDepth),
+		"string_bytes":   int64(stats.StringBytes),
+		"types":          types,
+		"largest_arrays": arrays,
+	}
+}
+
+// sortedCounts 按数量降序返回key，数量相同时按字典序
+func sortedCounts(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
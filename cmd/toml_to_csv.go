@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"unicode/utf8"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlToCsvParams struct {
+	Path      string `json:"path"`      // 要导出的表数组的路径
+	Delimiter string `json:"delimiter"` // 列分隔符
+}
+
+var toCsvParams = &TomlToCsvParams{}
+
+var tomlToCsvCmd = &cobra.Command{
+	Use:   "to-csv --path <path> [file]",
+	Short: "export an array of tables as csv",
+	Long:  "Write one csv row per element of the array at --path. The header is the union of all keys, nested tables become dotted columns such as dimensions.width and arrays are written as json.",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   tomlToCsvRun,
+}
+
+func init() {
+	tomlToCsvCmd.Flags().StringVarP(&toCsvParams.Path, "path", "p", "", "path of the array of tables, e.g. products")
+	tomlToCsvCmd.Flags().StringVar(&toCsvParams.Delimiter, "delimiter", ",", "column delimiter, a single character")
+	tomlToCsvCmd.MarkFlagRequired("path")
+	tomlCmd.AddCommand(tomlToCsvCmd)
+}
+
+func tomlToCsvRun(cmd *cobra.Command, args []string) {
+	comma, size := utf8.DecodeRuneInString(toCsvParams.Delimiter)
+	if toCsvParams.Delimiter == `\t` {
+		comma, size = '\t', len(toCsvParams.Delimiter)
+	}
+	if size == 0 || size != len(toCsvParams.Delimiter) || comma == '"' || comma == '\n' {
+		fail(codeUsage, exitUsage, "invalid delimiter %q, want a single character", toCsvParams.Delimiter)
+	}
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+
+	data, _ := loadToml()
+	value, ok, err := pkg.FindValue(data, toCsvParams.Path)
+	if err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	if !ok {
+		reportFileError(params.Input, &keyNotFoundError{paths: []string{toCsvParams.Path}})
+		os.Exit(exitData)
+	}
+	header, rows, err := pkg.DottedRows(value)
+	if err == nil {
+		var out string
+		if out, err = pkg.RenderCSV(header, rows, comma); err == nil {
+			writeResult(out)
+			return
+		}
+	}
+	fail(codeData, exitData, "%s", err)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+type TomlTreeParams struct {
+	Depth  int    `json:"depth"`  // 最大深度，0表示不限制
+	Style  string `json:"style"`  // 树的样式: box|indent
+	Values bool   `json:"values"` // 输出标量的值
+}
+
+var treeParams = &TomlTreeParams{}
+
+var tomlTreeCmd = &cobra.Command{
+	Use:   "tree [file]...",
+	Short: "print the structure of the document as a tree",
+	Long:  "Print tables, arrays with their element counts and the types of scalar values as a tree, for a quick look at the shape of a document.",
+	Run:   tomlTreeRun,
+}
+
+func init() {
+	tomlTreeCmd.Flags().IntVar(&treeParams.Depth, "depth", 0, "limit the depth of the tree, 0 means unlimited")
+	tomlTreeCmd.Flags().StringVar(&treeParams.Style, "style", "box", "tree style: box|indent")
+	tomlTreeCmd.Flags().BoolVarP(&treeParams.Values, "values", "v", false, "print scalar values next to their types")
+	tomlCmd.AddCommand(tomlTreeCmd)
+}
+
+func tomlTreeRun(cmd *cobra.Command, args []string) {
+	if treeParams.Style != "box" && treeParams.Style != "indent" {
+		fail(codeUsage, exitUsage, "unknown tree style %q, want one of box|indent", treeParams.Style)
+	}
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	runInputs(func(data map[string]any) (string, error) {
+		return pkg.Tree(data, pkg.TreeOptions{
+			Depth:  treeParams.Depth,
+			Box:    treeParams.Style == "box",
+			Values: treeParams.Values,
+		}), nil
+	})
+}
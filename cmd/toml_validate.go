@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/spf13/cobra"
+)
+
+var tomlValidateCmd = &cobra.Command{
+	Use:   "validate [file]...",
+	Short: "check toml files for syntax errors",
+	Long:  "Parse each file and print every error as file:line:col. Exits 0 when all files are valid, 2 on syntax errors and 3 on IO errors.",
+	Run:   tomlValidateRun,
+}
+
+func init() {
+	tomlCmd.AddCommand(tomlValidateCmd)
+}
+
+func tomlValidateRun(cmd *cobra.Command, args []string) {
+	if err := resolveInputs(args...); err != nil {
+		fail(codeUsage, exitUsage, "%s", err)
+	}
+	files := params.Inputs
+	if len(files) == 0 {
+		files = []string{""}
+	}
+
+	os.Exit(eachFile(files, validateFile))
+}
+
+// validateFile 校验单个文件，返回输出错误并给出退出码的函数，空路径或"-"表示标准输入
+func validateFile(file string) func() int {
+	name, src, err := readInput(file)
+	if err == nil {
+		_, err = pkg.DecodeTomlSource(name, src)
+	}
+	return func() int {
+		if err == nil {
+			return exitOK
+		}
+		if se, ok := pkg.AsSyntaxError(err); ok {
+			printDiagnostic(fileDiagnostic(name, err), fmt.Sprintf("%s:%d:%d: %s", name, se.Line, se.Col, se.Message))
+			return exitSyntax
+		}
+		printDiagnostic(fileDiagnostic(name, err), fmt.Sprintf("%s: %s", name, err))
+		return exitUsage
+	}
+}
@@ -0,0 +1,449 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+)
+
+var uiCmd = &cobra.Command{
+	Use:   "ui <file>",
+	Short: "browse and edit a toml file in a terminal UI",
+	Long:  "Browse the document as a tree with the selected value shown on the right. Press / to search keys, e to edit a scalar keeping its type, s to validate and save, q to quit.",
+	Args:  cobra.ExactArgs(1),
+	Run:   uiRun,
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+}
+
+// ui的输入模式
+const (
+	uiBrowse = iota
+	uiSearch
+	uiEdit
+)
+
+const uiHelp = "↑↓ move  ←→ fold  / search  e edit  s save  q quit"
+
+// uiNode 树中的一行
+type uiNode struct {
+	path  []pkg.PathKey
+	value any
+}
+
+// uiSession ui的状态
+type uiSession struct {
+	file     string
+	data     map[string]any
+	order    pkg.KeyOrder // 保存时沿用文件中key的顺序
+	modified bool
+
+	screen   tcell.Screen
+	expanded map[string]bool // 已展开的节点路径
+	nodes    []uiNode        // 当前可见的节点，搜索时为匹配结果
+	cursor   int
+	offset   int // 左侧第一行对应的节点下标
+
+	mode    int
+	input   []rune // 搜索或编辑时输入的内容
+	message string // 状态栏的提示
+	confirm bool   // 有未保存的修改时再按一次q退出
+}
+
+func uiRun(cmd *cobra.Command, args []string) {
+	if !pkg.IsTerminal(os.Stdout) {
+		fail(codeUsage, exitUsage, "aq ui requires a terminal")
+	}
+	data, order := loadTomlFile(args[0])
+	s := &uiSession{file: args[0], data: data, order: order, expanded: map[string]bool{}}
+
+	screen, err := tcell.NewScreen()
+	if err == nil {
+		err = screen.Init()
+	}
+	if err != nil {
+		fail(codeUsage, exitUsage, "init terminal error: %s", err)
+	}
+	defer screen.Fini()
+	s.screen = screen
+
+	s.refresh()
+	for {
+		s.draw()
+		ev, ok := screen.PollEvent().(*tcell.EventKey)
+		if !ok {
+			continue
+		}
+		if s.handleKey(ev) {
+			return
+		}
+	}
+}
+
+// refresh 按展开状态重新计算可见的节点
+func (s *uiSession) refresh() {
+	s.nodes = s.nodes[:0]
+	var add func(path []pkg.PathKey, value any)
+	add = func(path []pkg.PathKey, value any) {
+		s.nodes = append(s.nodes, uiNode{path: path, value: value})
+		if pkg.IsScalar(value) || !s.expanded[pkg.FormatPath(path)] {
+			return
+		}
+		uiChildren(path, value, add)
+	}
+	uiChildren(nil, s.data, add)
+	s.cursor = min(s.cursor, max(len(s.nodes)-1, 0))
+}
+
+// uiChildren 按顺序访问表或数组的直接子节点
+func uiChildren(path []pkg.PathKey, value any, fn func(path []pkg.PathKey, value any)) {
+	child := func(k pkg.PathKey) []pkg.PathKey {
+		return append(append([]pkg.PathKey(nil), path...), k)
+	}
+	if table, ok := value.(map[string]any); ok {
+		for _, k := range pkg.SortedKeys(table) {
+			fn(child(pkg.PathKey{Key: k}), table[k])
+		}
+		return
+	}
+	pkg.Walk(map[string]any{"": value}, func(p []pkg.PathKey, v any) bool {
+		if len(p) == 2 {
+			fn(child(p[1]), v)
+		}
+		return len(p) < 2
+	})
+}
+
+// search 按模糊匹配的分数列出所有路径
+func (s *uiSession) search() {
+	type match struct {
+		node  uiNode
+		score int
+	}
+	var matches []match
+	pattern := string(s.input)
+	pkg.Walk(s.data, func(path []pkg.PathKey, value any) bool {
+		if score, ok := pkg.FuzzyScore(pattern, pkg.FormatPath(path)); ok {
+			matches = append(matches, match{uiNode{path: path, value: value}, score})
+		}
+		return true
+	})
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	s.nodes = s.nodes[:0]
+	for _, m := range matches {
+		s.nodes = append(s.nodes, m.node)
+	}
+	s.cursor, s.offset = 0, 0
+}
+
+// reveal 展开路径上的所有父节点并选中该节点
+func (s *uiSession) reveal(path []pkg.PathKey) {
+	for i := 1; i < len(path); i++ {
+		s.expanded[pkg.FormatPath(path[:i])] = true
+	}
+	s.refresh()
+	target := pkg.FormatPath(path)
+	for i, n := range s.nodes {
+		if pkg.FormatPath(n.path) == target {
+			s.cursor = i
+		}
+	}
+}
+
+func (s *uiSession) selected() (uiNode, bool) {
+	if s.cursor < 0 || s.cursor >= len(s.nodes) {
+		return uiNode{}, false
+	}
+	return s.nodes[s.cursor], true
+}
+
+// handleKey 处理按键，返回true时退出
+func (s *uiSession) handleKey(ev *tcell.EventKey) bool {
+	switch s.mode {
+	case uiSearch, uiEdit:
+		s.handleInput(ev)
+		return false
+	}
+
+	quitting := s.confirm
+	s.confirm = false
+	s.message = ""
+	switch {
+	case ev.Key() == tcell.KeyUp || ev.Rune() == 'k':
+		s.cursor = max(s.cursor-1, 0)
+	case ev.Key() == tcell.KeyDown || ev.Rune() == 'j':
+		s.cursor = min(s.cursor+1, len(s.nodes)-1)
+	case ev.Key() == tcell.KeyPgUp:
+		s.cursor = max(s.cursor-s.paneHeight(), 0)
+	case ev.Key() == tcell.KeyPgDn:
+		s.cursor = min(s.cursor+s.paneHeight(), len(s.nodes)-1)
+	case ev.Key() == tcell.KeyRight || ev.Key() == tcell.KeyEnter || ev.Rune() == 'l':
+		if n, ok := s.selected(); ok && !pkg.IsScalar(n.value) {
+			s.expanded[pkg.FormatPath(n.path)] = true
+			s.refresh()
+		}
+	case ev.Key() == tcell.KeyLeft || ev.Rune() == 'h':
+		n, ok := s.selected()
+		if !ok {
+			break
+		}
+		if id := pkg.FormatPath(n.path); s.expanded[id] {
+			delete(s.expanded, id)
+			s.refresh()
+		} else if len(n.path) > 1 {
+			parent := n.path[:len(n.path)-1]
+			delete(s.expanded, pkg.FormatPath(parent))
+			s.reveal(parent)
+		}
+	case ev.Rune() == '/':
+		s.mode, s.input = uiSearch, nil
+		s.search()
+	case ev.Rune() == 'e':
+		n, ok := s.selected()
+		if !ok || !pkg.IsScalar(n.value) {
+			s.message = "only scalar values can be edited"
+			break
+		}
+		text, _ := pkg.FormatValue(n.value)
+		s.mode, s.input = uiEdit, []rune(text)
+	case ev.Rune() == 's':
+		if err := s.save(); err != nil {
+			s.message = "save failed: " + err.Error()
+		} else {
+			s.message = "saved " + s.file
+		}
+	case ev.Rune() == 'q' || ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyCtrlC:
+		if !s.modified || quitting {
+			return true
+		}
+		s.confirm = true
+		s.message = "unsaved changes, press q again to quit without saving or s to save"
+	}
+	return false
+}
+
+// handleInput 搜索和编辑模式下的输入
+func (s *uiSession) handleInput(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		if s.mode == uiSearch {
+			s.refresh()
+		}
+		s.mode = uiBrowse
+	case tcell.KeyEnter:
+		if s.mode == uiSearch {
+			s.mode = uiBrowse
+			if n, ok := s.selected(); ok {
+				s.reveal(n.path)
+			} else {
+				s.refresh()
+			}
+			return
+		}
+		if err := s.edit(string(s.input)); err != nil {
+			s.message = err.Error()
+			return
+		}
+		s.mode = uiBrowse
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(s.input) > 0 {
+			s.input = s.input[:len(s.input)-1]
+		}
+	case tcell.KeyUp, tcell.KeyDown:
+		// 编辑时不能移动选中的行，否则会把输入保存到其他key
+		if s.mode == uiEdit {
+			return
+		}
+		if ev.Key() == tcell.KeyUp {
+			s.cursor = max(s.cursor-1, 0)
+		} else {
+			s.cursor = min(s.cursor+1, len(s.nodes)-1)
+		}
+		return
+	case tcell.KeyRune:
+		s.input = append(s.input, ev.Rune())
+	default:
+		return
+	}
+	if s.mode == uiSearch {
+		s.search()
+	}
+}
+
+// edit 按原值的类型解析输入并修改选中的值
+func (s *uiSession) edit(text string) error {
+	n, ok := s.selected()
+	if !ok {
+		return nil
+	}
+	types := map[string]string{
+		"string":         "string",
+		"integer":        "int",
+		"float":          "float",
+		"boolean":        "bool",
+		"datetime":       "datetime",
+		"local-datetime": "datetime",
+		"local-date":     "datetime",
+		"local-time":     "datetime",
+	}
+	typ := pkg.TypeName(n.value)
+	value, err := pkg.ParseLiteral(text, types[typ])
+	if err == nil && pkg.TypeName(value) != typ {
+		err = fmt.Errorf("invalid %s value %q", typ, text)
+	}
+	if err != nil {
+		return err
+	}
+	if err := pkg.SetValue(s.data, n.path, value); err != nil {
+		return err
+	}
+	s.modified = true
+	s.refresh()
+	s.message = "set " + pkg.FormatPath(n.path) + ", press s to save"
+	return nil
+}
+
+// save 编码后重新解析一遍确认结果合法，再原子写回文件；标准输入、url和压缩文件无法写回
+func (s *uiSession) save() error {
+	if err := checkWritable(s.file); err != nil {
+		return fmt.Errorf("save %w", err)
+	}
+	out, err := pkg.EncodeTomlOrdered(s.data, s.order)
+	if err != nil {
+		return err
+	}
+	if _, err := pkg.DecodeToml(strings.NewReader(out)); err != nil {
+		return fmt.Errorf("encoded document is invalid: %w", err)
+	}
+	if err := pkg.WriteFileAtomic(s.file, []byte(out+"\n"), ""); err != nil {
+		return err
+	}
+	s.modified = false
+	return nil
+}
+
+func (s *uiSession) paneHeight() int {
+	_, h := s.screen.Size()
+	return max(h-1, 1)
+}
+
+// draw 左侧为树或者搜索结果，右侧为选中的值，最后一行为状态栏
+func (s *uiSession) draw() {
+	s.screen.Clear()
+	w, h := s.screen.Size()
+	paneH := s.paneHeight()
+	leftW := max(w*2/5, 20)
+
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+paneH {
+		s.offset = s.cursor - paneH + 1
+	}
+
+	dim := tcell.StyleDefault.Foreground(tcell.ColorGray)
+	for row := 0; row < paneH && s.offset+row < len(s.nodes); row++ {
+		i := s.offset + row
+		style := tcell.StyleDefault
+		if i == s.cursor {
+			style = style.Reverse(true)
+		}
+		x := uiText(s.screen, 0, row, leftW-1, s.nodeLabel(s.nodes[i]), style)
+		uiText(s.screen, x, row, leftW-1-x, " "+uiDescribe(s.nodes[i].value), dim)
+	}
+	for row := 0; row < paneH; row++ {
+		s.screen.SetContent(leftW-1, row, '│', nil, dim)
+	}
+
+	if n, ok := s.selected(); ok {
+		uiText(s.screen, leftW+1, 0, w-leftW-1, pkg.FormatPath(n.path), tcell.StyleDefault.Bold(true))
+		uiText(s.screen, leftW+1, 1, w-leftW-1, pkg.TypeName(n.value), dim)
+		for i, line := range strings.Split(uiValue(n.value), "\n") {
+			if 3+i >= paneH {
+				break
+			}
+			uiText(s.screen, leftW+1, 3+i, w-leftW-1, line, tcell.StyleDefault)
+		}
+	}
+
+	status := uiHelp
+	switch {
+	case s.mode == uiSearch:
+		status = "/" + string(s.input)
+	case s.mode == uiEdit:
+		n, _ := s.selected()
+		status = "edit " + pkg.FormatPath(n.path) + ": " + string(s.input)
+	case len(s.message) > 0:
+		status = s.message
+	}
+	if s.modified && s.mode == uiBrowse {
+		status = "[modified] " + status
+	}
+	uiText(s.screen, 0, h-1, w, status, tcell.StyleDefault.Reverse(true))
+	s.screen.Show()
+}
+
+// nodeLabel 树中显示缩进和展开标记，搜索结果显示完整路径
+func (s *uiSession) nodeLabel(n uiNode) string {
+	if s.mode == uiSearch {
+		return pkg.FormatPath(n.path)
+	}
+	marker := "  "
+	if !pkg.IsScalar(n.value) {
+		marker = "▸ "
+		if s.expanded[pkg.FormatPath(n.path)] {
+			marker = "▾ "
+		}
+	}
+	last := n.path[len(n.path)-1]
+	label := pkg.FormatPath([]pkg.PathKey{last})
+	if last.IsIndex {
+		label = fmt.Sprintf("[%d]", last.Index)
+	}
+	return strings.Repeat("  ", len(n.path)-1) + marker + label
+}
+
+// uiDescribe 节点后的简短说明，表和数组显示子节点数量
+func uiDescribe(value any) string {
+	count := 0
+	uiChildren(nil, value, func([]pkg.PathKey, any) { count++ })
+	if pkg.IsScalar(value) {
+		return pkg.TypeName(value)
+	}
+	return fmt.Sprintf("%s(%d)", pkg.TypeName(value), count)
+}
+
+// uiValue 右侧显示的值，表输出为toml，其余输出为json
+func uiValue(value any) string {
+	format := pkg.FormatJSON
+	if _, ok := value.(map[string]any); ok {
+		format = pkg.FormatTOML
+	}
+	out, err := pkg.RenderValue(value, format)
+	if err != nil {
+		return err.Error()
+	}
+	return out
+}
+
+// uiText 在x,y处输出不超过width显示宽度的文本，返回结束的列
+func uiText(screen tcell.Screen, x, y, width int, text string, style tcell.Style) int {
+	end := x + width
+	for _, r := range text {
+		rw := runewidth.RuneWidth(r)
+		if x+rw > end {
+			break
+		}
+		screen.SetContent(x, y, r, nil, style)
+		x += rw
+	}
+	return x
+}
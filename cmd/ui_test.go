@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// TestUIEditIgnoresCursorKeys 编辑时按上下键不改变选中的行，输入保存到开始编辑时的key
+func TestUIEditIgnoresCursorKeys(t *testing.T) {
+	s := &uiSession{data: map[string]any{"a": int64(1), "b": int64(2)}, expanded: map[string]bool{}}
+	s.refresh()
+
+	s.handleKey(tcell.NewEventKey(tcell.KeyRune, 'e', tcell.ModNone))
+	if s.mode != uiEdit {
+		t.Fatalf("mode = %d, want edit", s.mode)
+	}
+	s.handleKey(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	s.handleKey(tcell.NewEventKey(tcell.KeyRune, '0', tcell.ModNone))
+	s.handleKey(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+
+	if s.data["a"] != int64(10) || s.data["b"] != int64(2) {
+		t.Fatalf("data = %v, want a = 10 and b unchanged", s.data)
+	}
+}
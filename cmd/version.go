@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// 构建时通过ldflags注入，如
+// go build -ldflags "-X github.com/dzjyyds666/aq/cmd.Commit=$(git rev-parse --short HEAD) -X github.com/dzjyyds666/aq/cmd.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "v0.1"
+	Commit    = "HEAD"
+	BuildDate = "unknown"
+)
+
+type VersionParams struct {
+	JSON bool `json:"json"` // 以json输出
+}
+
+var versionParams = &VersionParams{}
+
+// VersionInfo 版本和构建信息
+type VersionInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit"`
+	CommitTime string `json:"commit_time,omitempty"` // go build记录的vcs提交时间，不是构建时间
+	BuildDate  string `json:"build_date"`
+	GoVersion  string `json:"go_version"`
+	Platform   string `json:"platform"`
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version number of Aq",
+	Long:  `All software has versions. This is Aq's`,
+	Run:   versionRun,
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionParams.JSON, "json", false, "print the build information as json")
+}
+
+func versionRun(cmd *cobra.Command, args []string) {
+	info := buildInfo()
+	if !versionParams.JSON {
+		fmt.Printf("Aq %s -- %s\n", info.Version, info.Commit)
+		fmt.Printf("built:       %s\n", info.BuildDate)
+		if len(info.CommitTime) > 0 {
+			fmt.Printf("commit time: %s\n", info.CommitTime)
+		}
+		fmt.Printf("go:          %s\n", info.GoVersion)
+		fmt.Printf("platform:    %s\n", info.Platform)
+		return
+	}
+	raw, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		fmt.Println("format version error:", err)
+		os.Exit(exitData)
+	}
+	fmt.Println(string(raw))
+}
+
+// buildInfo 汇总版本信息，未通过ldflags注入commit时使用go build记录的vcs信息；构建时间只能通过ldflags注入
+func buildInfo() VersionInfo {
+	info := VersionInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, s := range bi.Settings {
+		switch {
+		case s.Key == "vcs.revision" && info.Commit == "HEAD":
+			info.Commit = s.Value
+			if len(info.Commit) > 12 {
+				info.Commit = info.Commit[:12]
+			}
+		case s.Key == "vcs.time":
+			info.CommitTime = s.Value
+		}
+	}
+	return info
+}
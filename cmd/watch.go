@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dzjyyds666/aq/pkg"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+type WatchParams struct {
+	Watch       bool          `json:"watch"`        // 文件变化时重新执行
+	Interval    time.Duration `json:"interval"`     // 轮询间隔，为0时使用文件系统事件
+	ChangesOnly bool          `json:"changes_only"` // 只在结果变化时输出
+}
+
+var watchParams = &WatchParams{}
+
+// watchDebounce 合并编辑器保存时产生的多次事件
+const watchDebounce = 100 * time.Millisecond
+
+// addWatchFlags 为查询类命令注册--watch相关的参数
+func addWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&watchParams.Watch, "watch", "w", false, "re-run whenever the input file changes")
+	cmd.Flags().DurationVar(&watchParams.Interval, "interval", 0, "with --watch, poll the file at this interval instead of using file system events")
+	cmd.Flags().BoolVar(&watchParams.ChangesOnly, "changes-only", false, "with --watch, only print when the result changes")
+}
+
+// watchInput 每次输入文件变化后重新解析并输出render的结果，不会返回
+func watchInput(render func(data map[string]any) (string, error)) {
+	if len(params.Input) == 0 || params.Input == "-" || pkg.IsURL(params.Input) {
+		fail(codeUsage, exitUsage, "--watch requires a single local input file")
+	}
+
+	last, first := "", true
+	run := func() {
+		var out string
+		data, err := pkg.DecodeTomlFile(params.Input, fetchOptions.MaxSize)
+		if err == nil {
+			out, err = render(data)
+		}
+		if err != nil {
+			out = fmt.Sprint(err)
+		}
+		if watchParams.ChangesOnly && !first && out == last {
+			return
+		}
+		first, last = false, out
+		fmt.Printf("--- %s %s\n", params.Input, time.Now().Format(time.TimeOnly))
+		fmt.Println(out)
+	}
+
+	run()
+	var err error
+	if watchParams.Interval > 0 {
+		err = pollFile(params.Input, watchParams.Interval, run)
+	} else {
+		err = notifyFile(params.Input, run)
+	}
+	fail(codeUsage, exitUsage, "watch error: %s", err)
+}
+
+// notifyFile 监听文件所在目录，兼容编辑器先写临时文件再重命名的保存方式
+func notifyFile(file string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(abs)); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(0)
+	<-timer.C
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return fmt.Errorf("watcher closed")
+			}
+			if filepath.Clean(event.Name) == abs && event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				timer.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return fmt.Errorf("watcher closed")
+			}
+			return err
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
+
+// pollFile 按固定间隔检查文件的修改时间和大小
+func pollFile(file string, interval time.Duration, onChange func()) error {
+	stat := func() (time.Time, int64) {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, -1
+		}
+		return info.ModTime(), info.Size()
+	}
+
+	lastMod, lastSize := stat()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		mod, size := stat()
+		if !mod.Equal(lastMod) || size != lastSize {
+			lastMod, lastSize = mod, size
+			onChange()
+		}
+	}
+	return nil
+}
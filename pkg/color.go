@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"os"
+	"strings"
+)
+
+// ANSI颜色
+const (
+	colorReset  = "\x1b[0m"
+	colorKey    = "\x1b[34;1m"
+	colorString = "\x1b[32m"
+	colorNumber = "\x1b[36m"
+	colorBool   = "\x1b[33m"
+	colorNull   = "\x1b[90m"
+	colorHeader = "\x1b[35;1m"
+)
+
+// IsTerminal 判断文件是否连接到终端
+func IsTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// Colorize 为json或toml文本加上语法高亮，其余格式原样返回
+func Colorize(text, format string) string {
+	if format != FormatJSON && format != FormatTOML {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = colorizeLine(line, format)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func colorizeLine(line, format string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	if format == FormatTOML && strings.HasPrefix(trimmed, "[") {
+		return line[:len(line)-len(trimmed)] + colorHeader + trimmed + colorReset
+	}
+
+	var sb strings.Builder
+	i := 0
+	for i < len(line) {
+		c := line[i]
+		switch {
+		case c == '"' || c == '\'':
+			end := scanString(line, i)
+			sb.WriteString(paint(line[i:end], stringOrKey(line, end)))
+			i = end
+		case c == '#' && format == FormatTOML:
+			sb.WriteString(paint(line[i:], colorNull))
+			i = len(line)
+		case isWordChar(c):
+			end := i
+			for end < len(line) && isWordChar(line[end]) {
+				end++
+			}
+			sb.WriteString(paint(line[i:end], wordColor(line[i:end], line, end)))
+			i = end
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// scanString 返回从start开始的字符串字面量的结束位置
+func scanString(line string, start int) int {
+	quote := line[start]
+	i := start + 1
+	for i < len(line) && line[i] != quote {
+		if line[i] == '\\' && quote == '"' {
+			i++
+		}
+		i++
+	}
+	if i < len(line) {
+		i++
+	}
+	if i > len(line) {
+		i = len(line)
+	}
+	return i
+}
+
+// stringOrKey 字符串后面紧跟':'或'='时是key
+func stringOrKey(line string, end int) string {
+	rest := strings.TrimLeft(line[end:], " \t")
+	if strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, ".") {
+		return colorKey
+	}
+	return colorString
+}
+
+func wordColor(word, line string, end int) string {
+	switch word {
+	case "true", "false":
+		return colorBool
+	case "null":
+		return colorNull
+	}
+	if stringOrKey(line, end) == colorKey {
+		return colorKey
+	}
+	switch word[0] {
+	case '+', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return colorNumber
+	}
+	if word == "inf" || word == "nan" {
+		return colorNumber
+	}
+	return ""
+}
+
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_-+.:", c) >= 0
+}
+
+func paint(text, color string) string {
+	if len(color) == 0 {
+		return text
+	}
+	return color + text + colorReset
+}
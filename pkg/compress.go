@@ -0,0 +1,95 @@
+package pkg
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// 支持的压缩格式
+const (
+	CompressGzip  = "gzip"
+	CompressZstd  = "zstd"
+	CompressBzip2 = "bzip2"
+)
+
+// compressExts 压缩文件的扩展名
+var compressExts = map[string]string{
+	".gz":  CompressGzip,
+	".zst": CompressZstd,
+	".bz2": CompressBzip2,
+}
+
+// Compression 根据魔数判断数据的压缩格式，未压缩时返回空字符串
+func Compression(src []byte) string {
+	switch {
+	case bytes.HasPrefix(src, []byte{0x1f, 0x8b}):
+		return CompressGzip
+	case bytes.HasPrefix(src, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return CompressZstd
+	case isBzip2(src):
+		return CompressBzip2
+	}
+	return ""
+}
+
+// isBzip2 bzip2以"BZh"和块大小开头，之后是块或者流结束的魔数；只比较"BZh"会把以BZh开头的文本误判为压缩数据
+func isBzip2(src []byte) bool {
+	if len(src) < 10 || !bytes.HasPrefix(src, []byte("BZh")) || src[3] < '1' || src[3] > '9' {
+		return false
+	}
+	block := src[4:10]
+	return bytes.Equal(block, []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}) || bytes.Equal(block, []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90})
+}
+
+// Decompress 解压gzip、zstd或bzip2压缩的数据，未压缩的数据原样返回；
+// maxSize限制解压后的字节数，防止很小的压缩炸弹耗尽内存，0表示不限制
+func Decompress(src []byte, maxSize int64) ([]byte, error) {
+	var r io.Reader
+	switch Compression(src) {
+	case CompressGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(src))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	case CompressZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(src))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	case CompressBzip2:
+		r = bzip2.NewReader(bytes.NewReader(src))
+	default:
+		return src, nil
+	}
+	if maxSize > 0 {
+		r = io.LimitReader(r, maxSize+1)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize > 0 && int64(len(out)) > maxSize {
+		return nil, fmt.Errorf("decompressed data is larger than the limit of %d bytes", maxSize)
+	}
+	return out, nil
+}
+
+// TrimCompressExt 去掉路径末尾的压缩扩展名，如 app.toml.gz -> app.toml
+func TrimCompressExt(path string) string {
+	ext := filepath.Ext(path)
+	if _, ok := compressExts[strings.ToLower(ext)]; ok {
+		return strings.TrimSuffix(path, ext)
+	}
+	return path
+}
@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config 用户级配置文件中的默认设置
+type Config struct {
+	OutputFormat string            `toml:"output_format"` // 默认输出格式
+	Color        string            `toml:"color"`         // 默认语法高亮方式
+	ErrorFormat  string            `toml:"error_format"`  // 默认错误输出方式
+	Strict       bool              `toml:"strict"`        // 默认将警告视为错误
+	Aliases      map[string]string `toml:"aliases"`       // 查询别名，通过 -q @name 使用
+}
+
+// ConfigPath 返回配置文件路径，优先使用AQ_CONFIG，其次是 $XDG_CONFIG_HOME/aq/config.toml 和 ~/.config/aq/config.toml
+func ConfigPath() string {
+	if path := os.Getenv("AQ_CONFIG"); len(path) > 0 {
+		return path
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if len(dir) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "aq", "config.toml")
+}
+
+// LoadConfig 读取配置文件，文件不存在时返回空配置，存在未知的配置项时报错
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+	if len(path) == 0 {
+		return cfg, nil
+	}
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	meta, err := toml.Decode(string(src), cfg)
+	if _, ok := AsSyntaxError(err); ok {
+		return nil, &SourceError{Name: path, Src: src, Err: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		return nil, fmt.Errorf("unknown settings in %s: %s", path, strings.Join(keys, ", "))
+	}
+	return cfg, nil
+}
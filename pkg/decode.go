@@ -0,0 +1,307 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DetectFormat 根据文件扩展名判断格式，无法识别时返回空字符串；url会忽略查询参数，压缩文件使用压缩前的扩展名
+func DetectFormat(filePath string) string {
+	if IsURL(filePath) {
+		if u, err := url.Parse(filePath); err == nil {
+			filePath = u.Path
+		}
+	}
+	filePath = TrimCompressExt(filePath)
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".toml":
+		return FormatTOML
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return ""
+}
+
+// DecodeData 按照指定格式解码数据，数字统一为int64或float64，与toml解码的结果保持一致；null解码为nil，需要写成toml时用CheckNull检查。
+// 包含多个文档的yaml会返回错误，需要时使用DecodeDocuments
+func DecodeData(r io.Reader, format string) (any, error) {
+	docs, err := DecodeDocuments(r, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) > 1 {
+		return nil, fmt.Errorf("input has %d yaml documents, only one is supported here", len(docs))
+	}
+	return docs[0], nil
+}
+
+// DecodeDocuments 与DecodeData相同，但yaml中以---分隔的每个文档单独返回，toml和json总是只有一个文档
+func DecodeDocuments(r io.Reader, format string) ([]any, error) {
+	switch format {
+	case FormatTOML:
+		data, err := DecodeToml(r)
+		if err != nil {
+			return nil, err
+		}
+		return []any{data}, nil
+	case FormatJSON:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		var data any
+		if err := dec.Decode(&data); err != nil {
+			return nil, err
+		}
+		value, err := fromJSONValue(data, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []any{value}, nil
+	case FormatYAML:
+		var docs []any
+		dec := yaml.NewDecoder(r)
+		for {
+			var doc yaml.Node
+			if err := dec.Decode(&doc); err != nil {
+				if err != io.EOF {
+					return nil, err
+				}
+				break
+			}
+			value, err := fromYAMLNode(&doc, nil)
+			if err != nil {
+				if len(docs) > 0 {
+					return nil, fmt.Errorf("document %d: %w", len(docs)+1, err)
+				}
+				return nil, err
+			}
+			docs = append(docs, value)
+		}
+		if len(docs) == 0 {
+			return []any{map[string]any{}}, nil
+		}
+		return docs, nil
+	}
+	return nil, fmt.Errorf("unknown input format %q, want one of toml|json|yaml", format)
+}
+
+// DecodeSource 与DecodeDocuments相同，语法错误时返回带有源码片段的SourceError，name用于错误信息中的文件名
+func DecodeSource(name string, src []byte, format string) ([]any, error) {
+	docs, err := DecodeDocuments(bytes.NewReader(src), format)
+	if err == nil {
+		return docs, nil
+	}
+	switch format {
+	case FormatJSON:
+		err = jsonSyntaxError(src, err)
+	case FormatYAML:
+		err = yamlSyntaxError(err)
+	}
+	if _, ok := AsSyntaxError(err); ok {
+		return nil, &SourceError{Name: name, Src: src, Err: err}
+	}
+	return nil, err
+}
+
+// jsonSyntaxError 根据json错误中的字节偏移计算行列号，不是语法错误时原样返回
+func jsonSyntaxError(src []byte, err error) error {
+	var se *json.SyntaxError
+	switch {
+	case errors.As(err, &se):
+		// Offset为出错之前读取的字节数，出错的字符是其中最后一个
+		return positionError(src, max(int(se.Offset)-1, 0), se.Error())
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return positionError(src, len(bytes.TrimRight(src, " \t\r\n")), "unexpected end of json input")
+	}
+	return err
+}
+
+// positionError 生成指向src中第offset个字节的语法错误
+func positionError(src []byte, offset int, message string) *SyntaxError {
+	before := src[:min(offset, len(src))]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return &SyntaxError{Line: line, Col: col, Message: message}
+}
+
+// yamlLineError yaml语法错误的形式，如 yaml: line 3: mapping values are not allowed in this context
+var yamlLineError = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// yamlSyntaxError 从yaml的错误信息中取出行号，yaml只给出行号，没有行号时原样返回
+func yamlSyntaxError(err error) error {
+	m := yamlLineError.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(m[1])
+	return &SyntaxError{Line: line, Message: m[2]}
+}
+
+// CheckNull toml中没有null，值中含有nil时返回带路径的错误
+func CheckNull(value any) error {
+	if value == nil {
+		return fmt.Errorf("null value at the top level is not supported in toml")
+	}
+	var found []PathKey
+	walkValue(nil, value, func(path []PathKey, v any) bool {
+		if v == nil && found == nil {
+			found = path
+		}
+		return found == nil
+	})
+	if found != nil {
+		return fmt.Errorf("null value at %s is not supported in toml", FormatPath(found))
+	}
+	return nil
+}
+
+func fromJSONValue(value any, path []PathKey) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, k := range SortedKeys(v) {
+			conv, err := fromJSONValue(v[k], append(path, PathKey{Key: k}))
+			if err != nil {
+				return nil, err
+			}
+			v[k] = conv
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			conv, err := fromJSONValue(item, append(path, PathKey{Index: i, IsIndex: true}))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = conv
+		}
+		return v, nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		if !strings.ContainsAny(v.String(), ".eE") {
+			return nil, fmt.Errorf("integer %s at %s overflows int64", v, FormatPath(path))
+		}
+		return v.Float64()
+	}
+	return value, nil
+}
+
+// fromYAMLNode 按节点转换yaml，时间戳按写法对应到toml的时间类型，支持锚点和<<合并
+func fromYAMLNode(n *yaml.Node, path []PathKey) (any, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return map[string]any{}, nil
+		}
+		return fromYAMLNode(n.Content[0], path)
+	case yaml.AliasNode:
+		return fromYAMLNode(n.Alias, path)
+	case yaml.MappingNode:
+		out := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, item := n.Content[i], n.Content[i+1]
+			if key.Kind == yaml.AliasNode {
+				key = key.Alias
+			}
+			if key.ShortTag() == "!!merge" {
+				if err := mergeYAML(out, item, path); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			conv, err := fromYAMLNode(item, append(path, PathKey{Key: key.Value}))
+			if err != nil {
+				return nil, err
+			}
+			out[key.Value] = conv
+		}
+		return out, nil
+	case yaml.SequenceNode:
+		out := make([]any, len(n.Content))
+		for i, item := range n.Content {
+			conv, err := fromYAMLNode(item, append(path, PathKey{Index: i, IsIndex: true}))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = conv
+		}
+		return out, nil
+	}
+
+	switch n.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!timestamp":
+		return yamlTime(n)
+	}
+	var value any
+	if err := n.Decode(&value); err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case uint64:
+		return nil, fmt.Errorf("integer %d at %s overflows int64", v, FormatPath(path))
+	case float64:
+		if n.ShortTag() == "!!int" {
+			return nil, fmt.Errorf("integer %s at %s overflows int64", n.Value, FormatPath(path))
+		}
+	}
+	return value, nil
+}
+
+// mergeYAML 处理<<合并，已经存在的key不会被覆盖
+func mergeYAML(out map[string]any, n *yaml.Node, path []PathKey) error {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	sources := []*yaml.Node{n}
+	if n.Kind == yaml.SequenceNode {
+		sources = n.Content
+	}
+	for _, source := range sources {
+		conv, err := fromYAMLNode(source, path)
+		if err != nil {
+			return err
+		}
+		table, ok := conv.(map[string]any)
+		if !ok {
+			return fmt.Errorf("merge at %s needs a mapping", FormatPath(path))
+		}
+		for k, v := range table {
+			if _, ok := out[k]; !ok {
+				out[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+// yamlTime 只有日期时对应local date，没有时区时对应local datetime，其余为带时区的datetime
+func yamlTime(n *yaml.Node) (any, error) {
+	literal := strings.Replace(strings.TrimSpace(n.Value), " ", "T", 1)
+	if value, err := ParseLiteral(literal, ""); err == nil {
+		if _, ok := value.(time.Time); ok {
+			return value, nil
+		}
+	}
+	var t time.Time
+	if err := n.Decode(&t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
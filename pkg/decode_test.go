@@ -0,0 +1,135 @@
+package pkg
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeData(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		src    string
+		want   string // 按toml字面量比较，能区分int、float和时间的种类
+	}{
+		{"json numbers", FormatJSON, `{"i": 9007199254740993, "f": 1.5, "e": 1e3}`, `{ e = 1000.0, f = 1.5, i = 9007199254740993 }`},
+		{"yaml date", FormatYAML, "d: 2024-01-02", `{ d = 2024-01-02 }`},
+		{"yaml local datetime", FormatYAML, "d: 2024-01-02 10:00:00", `{ d = 2024-01-02T10:00:00 }`},
+		{"yaml datetime", FormatYAML, "d: 2024-01-02T10:00:00Z", `{ d = 2024-01-02T10:00:00Z }`},
+		{"yaml quoted date", FormatYAML, `d: "2024-01-02"`, `{ d = "2024-01-02" }`},
+		{"yaml merge", FormatYAML, "base: &b\n  a: 1\n  b: 2\nx:\n  <<: *b\n  b: 3", `{ base = { a = 1, b = 2 }, x = { a = 1, b = 3 } }`},
+		{"yaml empty", FormatYAML, "", `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := DecodeData(strings.NewReader(tt.src), tt.format)
+			if err != nil {
+				t.Fatalf("DecodeData error: %v", err)
+			}
+			got, err := TomlLiteral(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("DecodeData() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeDataErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		src    string
+		want   string
+	}{
+		{"json overflow", FormatJSON, `{"a": 12345678901234567890}`, "overflows int64"},
+		{"yaml overflow", FormatYAML, "a: 12345678901234567890", "overflows int64"},
+		{"yaml documents", FormatYAML, "a: 1\n---\nb: 2", "input has 2 yaml documents"},
+		{"yaml overflow in second document", FormatYAML, "a: 1\n---\nb: 12345678901234567890", "document 2: integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeData(strings.NewReader(tt.src), tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("DecodeData() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeDocuments(t *testing.T) {
+	docs, err := DecodeDocuments(strings.NewReader("kind: A\n---\nkind: B\n"), FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []any{map[string]any{"kind": "A"}, map[string]any{"kind": "B"}}
+	if !reflect.DeepEqual(docs, want) {
+		t.Fatalf("DecodeDocuments() = %v, want %v", docs, want)
+	}
+}
+
+func TestCheckNull(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		src    string
+		want   string // 为空时表示没有null
+	}{
+		{"json nested", FormatJSON, `{"a": {"b": null}}`, "null value at a.b"},
+		{"json array", FormatJSON, `{"a": [1, null]}`, "null value at a[1]"},
+		{"json top level", FormatJSON, `null`, "null value at the top level"},
+		{"yaml tilde", FormatYAML, "a:\n  b: ~", "null value at a.b"},
+		{"yaml empty value", FormatYAML, "a:", "null value at a"},
+		{"yaml quoted", FormatYAML, `a: "null"`, ""},
+		{"json no null", FormatJSON, `{"a": [1, {"b": false}]}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := DecodeData(strings.NewReader(tt.src), tt.format)
+			if err != nil {
+				t.Fatalf("DecodeData error: %v", err)
+			}
+			err = CheckNull(data)
+			switch {
+			case len(tt.want) == 0 && err != nil:
+				t.Fatalf("CheckNull() = %v, want nil", err)
+			case len(tt.want) > 0 && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Fatalf("CheckNull() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeSourceSyntaxError(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		src       string
+		line, col int
+	}{
+		{"json invalid character", FormatJSON, "{\n  \"a\": 1,\n  \"b\": ,\n}", 3, 8},
+		{"json truncated", FormatJSON, "{\"a\": [1,\n", 1, 10},
+		{"yaml mapping", FormatYAML, "x: 1\na: b: c\n", 2, 0},
+		{"toml missing value", FormatTOML, "a = 1\nb =\n", 2, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeSource("in", []byte(tt.src), tt.format)
+			var srcErr *SourceError
+			if !errors.As(err, &srcErr) {
+				t.Fatalf("DecodeSource() error = %v, want a SourceError", err)
+			}
+			se, ok := AsSyntaxError(err)
+			if !ok {
+				t.Fatalf("AsSyntaxError(%v) = false", err)
+			}
+			if se.Line != tt.line || se.Col != tt.col {
+				t.Fatalf("position = %d:%d, want %d:%d", se.Line, se.Col, tt.line, tt.col)
+			}
+		})
+	}
+}
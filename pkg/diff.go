@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// 差异类型
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change 两个文档在某个路径上的差异
+type Change struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// Diff 按key路径比较两个文档，忽略key的顺序和格式
+func Diff(a, b map[string]any) []Change {
+	var changes []Change
+	diffValue(nil, a, b, &changes)
+	return changes
+}
+
+func diffValue(path []PathKey, a, b any, changes *[]Change) {
+	if ta, ok := a.(map[string]any); ok {
+		if tb, ok := b.(map[string]any); ok {
+			diffTable(path, ta, tb, changes)
+			return
+		}
+	}
+	if aa, ok := toArray(a); ok {
+		if ab, ok := toArray(b); ok {
+			diffArray(path, aa, ab, changes)
+			return
+		}
+	}
+	if !EqualValue(a, b) {
+		*changes = append(*changes, Change{Path: FormatPath(path), Type: ChangeChanged, Old: a, New: b})
+	}
+}
+
+func diffTable(path []PathKey, a, b map[string]any, changes *[]Change) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		sub := appendPath(path, PathKey{Key: k})
+		va, inA := a[k]
+		vb, inB := b[k]
+		switch {
+		case !inB:
+			*changes = append(*changes, Change{Path: FormatPath(sub), Type: ChangeRemoved, Old: va})
+		case !inA:
+			*changes = append(*changes, Change{Path: FormatPath(sub), Type: ChangeAdded, New: vb})
+		default:
+			diffValue(sub, va, vb, changes)
+		}
+	}
+}
+
+func diffArray(path []PathKey, a, b []any, changes *[]Change) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		sub := appendPath(path, PathKey{Index: i, IsIndex: true})
+		switch {
+		case i >= len(b):
+			*changes = append(*changes, Change{Path: FormatPath(sub), Type: ChangeRemoved, Old: a[i]})
+		case i >= len(a):
+			*changes = append(*changes, Change{Path: FormatPath(sub), Type: ChangeAdded, New: b[i]})
+		default:
+			diffValue(sub, a[i], b[i], changes)
+		}
+	}
+}
+
+// appendPath 复制后追加，避免多个分支共享底层数组
+func appendPath(path []PathKey, key PathKey) []PathKey {
+	out := make([]PathKey, len(path), len(path)+1)
+	copy(out, path)
+	return append(out, key)
+}
+
+// EqualValue 比较两个标量是否相等，NaN视为相等；带时区的时间按时刻比较（…Z 与 …+00:00 相等），
+// 本地时间、日期和时刻只与同一种类的值比较
+func EqualValue(a, b any) bool {
+	switch va := a.(type) {
+	case float64:
+		vb, ok := b.(float64)
+		if !ok {
+			return false
+		}
+		if math.IsNaN(va) && math.IsNaN(vb) {
+			return true
+		}
+		return va == vb
+	case time.Time:
+		vb, ok := b.(time.Time)
+		return ok && TypeName(va) == TypeName(vb) && va.Equal(vb)
+	case int64, string, bool:
+		return a == b
+	}
+	return false
+}
+
+// InlineValue 将值渲染为单行文本，使用toml字面量（如 1.0、"x"、{ a = 1 }），无法表示时输出为紧凑的json
+func InlineValue(value any) string {
+	if literal, err := TomlLiteral(value); err == nil {
+		return literal
+	}
+	raw, err := json.Marshal(NormalizeValue(value))
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(raw)
+}
@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Change
+	}{
+		{"equal", "a = 1\n[t]\nb = \"x\"", "a = 1 # comment\nt = { b = 'x' }", nil},
+		{"added", "a = 1", "a = 1\nb = 2", []Change{{Path: "b", Type: ChangeAdded, New: int64(2)}}},
+		{"removed", "a = 1\nb = 2", "a = 1", []Change{{Path: "b", Type: ChangeRemoved, Old: int64(2)}}},
+		{"changed", "a = 1", "a = 2", []Change{{Path: "a", Type: ChangeChanged, Old: int64(1), New: int64(2)}}},
+		{"int vs float", "a = 1", "a = 1.0", []Change{{Path: "a", Type: ChangeChanged, Old: int64(1), New: 1.0}}},
+		{"nested", "[s]\nport = 80", "[s]\nport = 81", []Change{{Path: "s.port", Type: ChangeChanged, Old: int64(80), New: int64(81)}}},
+		{"quoted key", "\"a.b\" = 1", "\"a.b\" = 2", []Change{{Path: `"a.b"`, Type: ChangeChanged, Old: int64(1), New: int64(2)}}},
+		{"array grow", "a = [1]", "a = [1, 2]", []Change{{Path: "a[1]", Type: ChangeAdded, New: int64(2)}}},
+		{"array shrink", "a = [1, 2]", "a = [1]", []Change{{Path: "a[1]", Type: ChangeRemoved, Old: int64(2)}}},
+		{"array of tables", "[[s]]\nh = \"a\"\n[[s]]\nh = \"b\"", "[[s]]\nh = \"a\"\n[[s]]\nh = \"c\"", []Change{{Path: "s[1].h", Type: ChangeChanged, Old: "b", New: "c"}}},
+		{"same instant", "t = 2024-01-02T10:00:00Z", "t = 2024-01-02T12:00:00+02:00", nil},
+		{"nan", "a = nan", "a = nan", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := DecodeToml(strings.NewReader(tt.a))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := DecodeToml(strings.NewReader(tt.b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Diff(a, b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Diff() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqualValue(t *testing.T) {
+	local, _ := ParseLiteral("2024-01-02T10:00:00", "")
+	utc, _ := ParseLiteral("2024-01-02T10:00:00Z", "")
+	date, _ := ParseLiteral("2024-01-02", "")
+	midnight, _ := ParseLiteral("2024-01-02T00:00:00", "")
+	tests := []struct {
+		name string
+		a, b any
+		want bool
+	}{
+		{"int", int64(1), int64(1), true},
+		{"int and float", int64(1), 1.0, false},
+		{"nan", math.NaN(), math.NaN(), true},
+		{"string", "a", "b", false},
+		{"local and offset", local, utc, false},
+		{"date and datetime", date, midnight, false},
+		{"same local", local, local, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualValue(tt.a, tt.b); got != tt.want {
+				t.Fatalf("EqualValue(%v, %v) = %t, want %t", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInlineValue(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{int64(1), "1"},
+		{1.0, "1.0"},
+		{"x", `"x"`},
+		{[]any{int64(1), "a"}, `[1, "a"]`},
+		{map[string]any{"a": int64(1), "b c": true}, `{ a = 1, "b c" = true }`},
+	}
+	for _, tt := range tests {
+		if got := InlineValue(tt.value); got != tt.want {
+			t.Errorf("InlineValue(%#v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package pkg
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// DocSection 文档中的一节，对应一个表
+type DocSection struct {
+	Path        string     // 表的路径，数组表的元素写作 servers[]，根表为空
+	Description string     // 表的说明
+	Entries     []DocEntry // 表中不是表的key
+}
+
+// DocEntry 文档中的一个key
+type DocEntry struct {
+	Key         string
+	Type        string
+	Default     string // 默认值或示例值
+	Description string
+	Required    bool
+}
+
+// DocFromData 根据配置文件生成文档，src中紧挨着key的注释（上方或行尾）作为说明，数组表按第一个元素生成
+func DocFromData(data map[string]any, src []byte) []DocSection {
+	d := &dataDoc{lines: LocateKeys(src), src: strings.Split(string(src), "\n")}
+	d.visit(nil, "", data)
+	return d.sections
+}
+
+type dataDoc struct {
+	lines    KeyLines
+	src      []string
+	sections []DocSection
+}
+
+func (d *dataDoc) visit(path []PathKey, display string, table map[string]any) {
+	section := DocSection{Path: display, Description: d.comment(path)}
+	var tables []string
+	for _, k := range SortedKeys(table) {
+		v := table[k]
+		if docNested(v) != nil {
+			tables = append(tables, k)
+			continue
+		}
+		p := append(append([]PathKey(nil), path...), PathKey{Key: k})
+		section.Entries = append(section.Entries, DocEntry{
+			Key:         docKey(display, k),
+			Type:        TypeName(v),
+			Default:     InlineValue(v),
+			Description: d.comment(p),
+		})
+	}
+	if len(section.Entries) > 0 || len(section.Description) > 0 {
+		d.sections = append(d.sections, section)
+	}
+
+	for _, k := range tables {
+		p := append(append([]PathKey(nil), path...), PathKey{Key: k})
+		sub := docKey(display, k)
+		if nested, ok := table[k].(map[string]any); ok {
+			d.visit(p, sub, nested)
+			continue
+		}
+		d.visit(append(p, PathKey{Index: 0, IsIndex: true}), sub+"[]", docNested(table[k]))
+	}
+}
+
+// docKey 节的路径加上key，key需要时加引号
+func docKey(display, key string) string {
+	k := FormatPath([]PathKey{{Key: key}})
+	if len(display) > 0 {
+		return display + "." + k
+	}
+	return k
+}
+
+// docNested 表或者表的数组（返回第一个元素）需要单独成节
+func docNested(value any) map[string]any {
+	if table, ok := value.(map[string]any); ok {
+		return table
+	}
+	if arr, ok := toArray(value); ok && len(arr) > 0 {
+		if table, ok := arr[0].(map[string]any); ok {
+			return table
+		}
+	}
+	return nil
+}
+
+// comment 返回key所在行的行尾注释，没有时返回紧挨在上方的连续注释行
+func (d *dataDoc) comment(path []PathKey) string {
+	line, ok := d.lines[FormatPath(path)]
+	if !ok || line > len(d.src) {
+		return ""
+	}
+	if c := trailingComment(d.src[line-1]); len(c) > 0 {
+		return c
+	}
+	var parts []string
+	for i := line - 2; i >= 0; i-- {
+		text := strings.TrimSpace(d.src[i])
+		if !strings.HasPrefix(text, "#") {
+			break
+		}
+		parts = append([]string{strings.TrimSpace(strings.TrimLeft(text, "#"))}, parts...)
+	}
+	return strings.Join(parts, " ")
+}
+
+// HasComments 判断toml源文本中是否有注释，多行字符串中的#不算
+func HasComments(src []byte) bool {
+	var multiline string
+	for _, line := range strings.Split(string(src), "\n") {
+		if len(multiline) > 0 {
+			if strings.Contains(line, multiline) {
+				multiline = ""
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || len(trailingComment(line)) > 0 {
+			return true
+		}
+		if eq := findAssign(trimmed); eq >= 0 {
+			value := strings.TrimSpace(trimmed[eq+1:])
+			for _, quote := range []string{`"""`, `'''`} {
+				if strings.HasPrefix(value, quote) && !strings.Contains(value[3:], quote) {
+					multiline = quote
+				}
+			}
+		}
+	}
+	return false
+}
+
+// trailingComment 返回不在字符串中的#之后的内容
+func trailingComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			if len(strings.TrimSpace(line[:i])) == 0 {
+				return ""
+			}
+			return strings.TrimSpace(line[i+1:])
+		}
+	}
+	return ""
+}
+
+// DocFromSchema 根据schema生成文档，说明、默认值和示例取自schema中的description、default和examples
+func DocFromSchema(s *Schema) []DocSection {
+	var sections []DocSection
+	var visit func(display string, s *Schema)
+	visit = func(display string, s *Schema) {
+		section := DocSection{Path: display, Description: s.Description}
+		required := map[string]bool{}
+		for _, k := range s.Required {
+			required[k] = true
+		}
+		var nested []string
+		for _, k := range schemaKeys(s.Properties) {
+			prop := s.Properties[k]
+			if schemaNested(prop) != nil {
+				nested = append(nested, k)
+				continue
+			}
+			section.Entries = append(section.Entries, DocEntry{
+				Key:         docKey(display, k),
+				Type:        schemaTypeText(prop),
+				Default:     schemaDefault(prop),
+				Description: prop.Description,
+				Required:    required[k],
+			})
+		}
+		if len(section.Entries) > 0 || len(section.Description) > 0 {
+			sections = append(sections, section)
+		}
+		for _, k := range nested {
+			sub := docKey(display, k)
+			prop := s.Properties[k]
+			if prop.Items != nil && schemaNested(prop.Items) != nil {
+				sub += "[]"
+			}
+			visit(sub, schemaNested(prop))
+		}
+	}
+	visit("", s)
+	return sections
+}
+
+// schemaNested 对象或者对象的数组（返回元素的schema）需要单独成节
+func schemaNested(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	if len(s.Properties) > 0 {
+		return s
+	}
+	if s.Items != nil && len(s.Items.Properties) > 0 {
+		return s.Items
+	}
+	return nil
+}
+
+func schemaTypeText(s *Schema) string {
+	text := strings.Join(s.Type, "|")
+	if len(s.Format) > 0 {
+		text += " (" + s.Format + ")"
+	}
+	if s.Items != nil && len(s.Items.Type) > 0 {
+		text += " of " + strings.Join(s.Items.Type, "|")
+	}
+	if len(s.Enum) > 0 {
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			values[i] = docJSON(v)
+		}
+		text += ": " + strings.Join(values, ", ")
+	}
+	return text
+}
+
+func schemaDefault(s *Schema) string {
+	switch {
+	case s.Default != nil:
+		return docJSON(s.Default)
+	case len(s.Examples) > 0:
+		return docJSON(s.Examples[0])
+	}
+	return ""
+}
+
+func docJSON(value any) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
+
+// RenderDoc 以markdown输出文档，每节一个标题和表格，required为true时输出是否必填的一列
+func RenderDoc(title string, sections []DocSection, required bool) string {
+	var parts []string
+	if len(title) > 0 {
+		parts = append(parts, "# "+title)
+	}
+	header := []string{"Key", "Type", "Default", "Description"}
+	if required {
+		header = append(header, "Required")
+	}
+	for _, section := range sections {
+		if len(section.Path) > 0 {
+			parts = append(parts, "## `"+section.Path+"`")
+		}
+		if len(section.Description) > 0 {
+			parts = append(parts, section.Description)
+		}
+		if len(section.Entries) == 0 {
+			continue
+		}
+		rows := make([][]string, len(section.Entries))
+		for i, e := range section.Entries {
+			rows[i] = []string{"`" + e.Key + "`", e.Type, docCode(e.Default), e.Description}
+			if required && e.Required {
+				rows[i] = append(rows[i], "yes")
+			} else if required {
+				rows[i] = append(rows[i], "no")
+			}
+		}
+		parts = append(parts, renderMarkdown(header, rows))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func docCode(text string) string {
+	if len(text) == 0 {
+		return ""
+	}
+	return "`" + text + "`"
+}
@@ -0,0 +1,211 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// SetValue 按路径设置值，中间不存在的表会自动创建；数组下标等于长度时追加元素
+func SetValue(data map[string]any, keys []PathKey, value any) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("empty path")
+	}
+	if keys[0].IsIndex {
+		return fmt.Errorf("path must start with a key")
+	}
+	_, err := setIn(data, keys, 0, value)
+	return err
+}
+
+func setIn(cur any, keys []PathKey, depth int, value any) (any, error) {
+	if depth == len(keys) {
+		return value, nil
+	}
+	k := keys[depth]
+	parent := FormatPath(keys[:depth])
+	if k.IsIndex {
+		arr, ok := toArray(cur)
+		if !ok {
+			return nil, fmt.Errorf("cannot index %s: not an array", parent)
+		}
+		if k.Index > len(arr) {
+			return nil, fmt.Errorf("index %s%s out of range (len %d)", parent, k, len(arr))
+		}
+		var elem any
+		if k.Index < len(arr) {
+			elem = arr[k.Index]
+		} else if depth+1 < len(keys) && !keys[depth+1].IsIndex {
+			elem = map[string]any{}
+		}
+		next, err := setIn(elem, keys, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+		if k.Index == len(arr) {
+			arr = append(arr, next)
+		} else {
+			arr[k.Index] = next
+		}
+		return fromArray(arr), nil
+	}
+
+	if cur == nil {
+		cur = map[string]any{}
+	}
+	table, ok := cur.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot set key %q: %s is not a table", k.Key, parent)
+	}
+	child, exist := table[k.Key]
+	if !exist && depth+1 < len(keys) && keys[depth+1].IsIndex {
+		child = []any{}
+	}
+	next, err := setIn(child, keys, depth+1, value)
+	if err != nil {
+		return nil, err
+	}
+	table[k.Key] = next
+	return table, nil
+}
+
+// toArray 将两种数组形式统一为[]any
+func toArray(v any) ([]any, bool) {
+	switch arr := v.(type) {
+	case []any:
+		return arr, true
+	case []map[string]any:
+		out := make([]any, len(arr))
+		for i, item := range arr {
+			out[i] = item
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// fromArray 如果数组元素全部是表，恢复为[]map[string]any，编码时输出为[[table]]
+func fromArray(arr []any) any {
+	if len(arr) == 0 {
+		return arr
+	}
+	tables := make([]map[string]any, len(arr))
+	for i, item := range arr {
+		t, ok := item.(map[string]any)
+		if !ok {
+			return arr
+		}
+		tables[i] = t
+	}
+	return tables
+}
+
+// ParseLiteral 将命令行中的字面量转换为toml值，typ为空时自动推断类型
+func ParseLiteral(literal, typ string) (any, error) {
+	if typ == "string" {
+		return literal, nil
+	}
+
+	var doc map[string]any
+	_, err := toml.Decode("v = "+literal, &doc)
+	if err != nil {
+		if typ == "" {
+			// 无法识别的字面量按字符串处理
+			return literal, nil
+		}
+		return nil, fmt.Errorf("invalid %s value %q", typ, literal)
+	}
+	value := doc["v"]
+
+	switch typ {
+	case "":
+		return value, nil
+	case "int":
+		if _, ok := value.(int64); ok {
+			return value, nil
+		}
+	case "float":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		}
+	case "bool":
+		if _, ok := value.(bool); ok {
+			return value, nil
+		}
+	case "datetime":
+		if _, ok := value.(time.Time); ok {
+			return value, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q, want one of string|int|float|bool|datetime", typ)
+	}
+	return nil, fmt.Errorf("invalid %s value %q", typ, literal)
+}
+
+// EncodeToml 将数据编码为toml文本
+func EncodeToml(data map[string]any) (string, error) {
+	var sb strings.Builder
+	enc := toml.NewEncoder(&sb)
+	enc.Indent = ""
+	if err := enc.Encode(data); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// DeleteValue 按路径删除key、表或数组元素，pruneEmpty为true时删除因此变空的表
+func DeleteValue(data map[string]any, keys []PathKey, pruneEmpty bool) (bool, error) {
+	if len(keys) == 0 {
+		return false, fmt.Errorf("empty path")
+	}
+	_, found := deleteIn(data, keys, pruneEmpty)
+	return found, nil
+}
+
+func deleteIn(cur any, keys []PathKey, pruneEmpty bool) (any, bool) {
+	k := keys[0]
+	if k.IsIndex {
+		arr, ok := toArray(cur)
+		if !ok || k.Index >= len(arr) {
+			return cur, false
+		}
+		if len(keys) == 1 {
+			arr = append(arr[:k.Index], arr[k.Index+1:]...)
+			return fromArray(arr), true
+		}
+		next, found := deleteIn(arr[k.Index], keys[1:], pruneEmpty)
+		if !found {
+			return cur, false
+		}
+		arr[k.Index] = next
+		return fromArray(arr), true
+	}
+
+	table, ok := cur.(map[string]any)
+	if !ok {
+		return cur, false
+	}
+	child, exist := table[k.Key]
+	if !exist {
+		return cur, false
+	}
+	if len(keys) == 1 {
+		delete(table, k.Key)
+		return table, true
+	}
+	next, found := deleteIn(child, keys[1:], pruneEmpty)
+	if !found {
+		return cur, false
+	}
+	if t, ok := next.(map[string]any); ok && pruneEmpty && len(t) == 0 {
+		delete(table, k.Key)
+	} else {
+		table[k.Key] = next
+	}
+	return table, true
+}
@@ -0,0 +1,162 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// KeyOrder 返回表中key的输出顺序，path为表的路径（数组中的表使用数组的路径）
+type KeyOrder func(path []string, table map[string]any) []string
+
+// SortedOrder 按字典序输出
+func SortedOrder(path []string, table map[string]any) []string {
+	return SortedKeys(table)
+}
+
+// DecodeTomlOrdered 解码toml并记录key在文档中出现的顺序
+func DecodeTomlOrdered(r io.Reader) (map[string]any, KeyOrder, error) {
+	data := make(map[string]any)
+	meta, err := toml.NewDecoder(r).Decode(&data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	children := map[string][]string{}
+	seen := map[string]bool{}
+	for _, key := range meta.Keys() {
+		id := strings.Join(key, "\x00")
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		parent := strings.Join(key[:len(key)-1], "\x00")
+		children[parent] = append(children[parent], key[len(key)-1])
+	}
+
+	order := func(path []string, table map[string]any) []string {
+		keys := make([]string, 0, len(table))
+		listed := map[string]bool{}
+		for _, k := range children[strings.Join(path, "\x00")] {
+			if _, ok := table[k]; ok && !listed[k] {
+				keys = append(keys, k)
+				listed[k] = true
+			}
+		}
+		// 文档中没有出现过的key（比如后来新增的）按字典序放在最后
+		var rest []string
+		for k := range table {
+			if !listed[k] {
+				rest = append(rest, k)
+			}
+		}
+		sort.Strings(rest)
+		return append(keys, rest...)
+	}
+	return data, order, nil
+}
+
+// EncodeTomlOrdered 按照order给出的key顺序编码toml，每个表先输出键值对，再输出子表
+func EncodeTomlOrdered(data map[string]any, order KeyOrder) (string, error) {
+	var sb strings.Builder
+	if err := encodeTable(&sb, nil, data, order, ""); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// encodeTable header为空时表示不需要输出表头（根表或者只包含子表的表）
+func encodeTable(sb *strings.Builder, path []string, table map[string]any, order KeyOrder, header string) error {
+	keys := order(path, table)
+
+	var sections []string
+	if len(header) > 0 {
+		sb.WriteString("\n" + header + "\n")
+	}
+	for _, k := range keys {
+		v := table[k]
+		if isTableLike(v) {
+			sections = append(sections, k)
+			continue
+		}
+		literal, err := TomlLiteral(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", strings.Join(append(path, k), "."), err)
+		}
+		sb.WriteString(quoteKey(k) + " = " + literal + "\n")
+	}
+
+	for _, k := range sections {
+		sub := append(append([]string(nil), path...), k)
+		name := headerName(sub)
+		switch v := table[k].(type) {
+		case map[string]any:
+			h := "[" + name + "]"
+			if len(v) > 0 && !hasDirectValues(v) {
+				h = ""
+			}
+			if err := encodeTable(sb, sub, v, order, h); err != nil {
+				return err
+			}
+		default:
+			arr, _ := toArray(v)
+			for _, item := range arr {
+				if err := encodeTable(sb, sub, item.(map[string]any), order, "[["+name+"]]"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isTableLike 表或者元素全部是表的非空数组，需要以[table]或[[table]]形式输出
+func isTableLike(v any) bool {
+	if _, ok := v.(map[string]any); ok {
+		return true
+	}
+	arr, ok := toArray(v)
+	if !ok || len(arr) == 0 {
+		return false
+	}
+	for _, item := range arr {
+		if _, ok := item.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// hasDirectValues 表中是否有需要直接输出的键值对
+func hasDirectValues(table map[string]any) bool {
+	for _, v := range table {
+		if !isTableLike(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func headerName(path []string) string {
+	parts := make([]string, len(path))
+	for i, k := range path {
+		parts[i] = quoteKey(k)
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteKey 需要时给key加上引号
+func quoteKey(key string) string {
+	if isBareKey(key) {
+		return key
+	}
+	literal, err := TomlLiteral(key)
+	if err != nil {
+		return strconv.Quote(key)
+	}
+	return literal
+}
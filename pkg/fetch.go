@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// FetchOptions 读取远程文件时的设置
+type FetchOptions struct {
+	Timeout time.Duration // 整个请求的超时时间
+	Headers []string      // 额外的请求头，格式为 "Name: value"
+	Token   string        // 设置后以 Authorization: Bearer 发送
+	MaxSize int64         // 响应体的最大字节数，0表示不限制
+}
+
+// FetchError 读取远程文件失败
+type FetchError struct {
+	URL string
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("fetch %s: %s", e.URL, e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// IsURL 判断输入是否为http或https地址
+func IsURL(input string) bool {
+	return strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://")
+}
+
+// FetchURL 通过GET读取远程文件的内容，非2xx的响应和超过大小限制都视为错误
+func FetchURL(url string, opts FetchOptions) ([]byte, error) {
+	src, err := fetchURL(url, opts)
+	if err != nil {
+		return nil, &FetchError{URL: url, Err: err}
+	}
+	return src, nil
+}
+
+func fetchURL(url string, opts FetchOptions) ([]byte, error) {
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, header := range opts.Headers {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid header %q, want \"Name: value\"", header)
+		}
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	if len(opts.Token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+opts.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if opts.MaxSize > 0 && resp.ContentLength > opts.MaxSize {
+		return nil, fmt.Errorf("response is %d bytes, larger than the limit of %d", resp.ContentLength, opts.MaxSize)
+	}
+
+	var body io.Reader = resp.Body
+	if opts.MaxSize > 0 {
+		body = io.LimitReader(resp.Body, opts.MaxSize+1)
+	}
+	src, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxSize > 0 && int64(len(src)) > opts.MaxSize {
+		return nil, fmt.Errorf("response is larger than the limit of %d bytes", opts.MaxSize)
+	}
+	return src, nil
+}
@@ -1,6 +1,10 @@
 package pkg
 
-import "os"
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
 
 // CheckFileExist 检查文件是否存在
 func CheckFileExist(filePath string) (bool, error) {
@@ -13,3 +17,79 @@ func CheckFileExist(filePath string) (bool, error) {
 	}
 	return true, nil
 }
+
+// IsStdinPiped 判断标准输入是否来自管道或重定向，而不是终端
+func IsStdinPiped() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice == 0
+}
+
+// WriteFileAtomic 先写入同目录下的临时文件，fsync后再重命名覆盖原文件，保证读者不会看到写了一半的内容，
+// 已存在的文件会保留原有的权限，符号链接会写入其指向的文件。
+// backupSuffix不为空且原文件存在时，原文件会先复制一份到 filePath+backupSuffix
+func WriteFileAtomic(filePath string, data []byte, backupSuffix string) error {
+	if target, err := filepath.EvalSymlinks(filePath); err == nil {
+		filePath = target
+	}
+	mode := os.FileMode(0644)
+	info, err := os.Stat(filePath)
+	if err == nil {
+		mode = info.Mode().Perm()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if len(backupSuffix) > 0 && info != nil {
+		old, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filePath+backupSuffix, old, mode); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(filePath))
+	return nil
+}
+
+// syncDir 将目录项的修改落盘，使重命名在断电后也能保留；部分平台不支持对目录fsync，忽略错误
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
+
+// TimestampSuffix 生成带时间戳的备份后缀，如 .20060102-150405.bak
+func TimestampSuffix(t time.Time) string {
+	return "." + t.Format("20060102-150405") + ".bak"
+}
@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FlatEntry 展开后的一项，Value是标量，或者空的table/array
+type FlatEntry struct {
+	Path  []PathKey
+	Value any
+}
+
+// Flatten 将文档展开为叶子节点列表，顺序与Walk一致
+func Flatten(data map[string]any) []FlatEntry {
+	var entries []FlatEntry
+	Walk(data, func(path []PathKey, value any) bool {
+		switch v := value.(type) {
+		case map[string]any:
+			if len(v) == 0 {
+				entries = append(entries, FlatEntry{Path: path, Value: v})
+			}
+			return true
+		}
+		if arr, ok := toArray(value); ok {
+			if len(arr) == 0 {
+				entries = append(entries, FlatEntry{Path: path, Value: value})
+			}
+			return true
+		}
+		entries = append(entries, FlatEntry{Path: path, Value: value})
+		return false
+	})
+	return entries
+}
+
+// Unflatten 读取 path = value 形式的行并还原为文档，空行和#开头的行会被忽略
+func Unflatten(r io.Reader) (map[string]any, error) {
+	data := make(map[string]any)
+	reader := bufio.NewReader(r)
+	for lineNo := 1; ; lineNo++ {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if text := strings.TrimSpace(line); len(text) > 0 && !strings.HasPrefix(text, "#") {
+			if perr := unflattenLine(data, text); perr != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, perr)
+			}
+		}
+		if err == io.EOF {
+			return data, nil
+		}
+	}
+}
+
+func unflattenLine(data map[string]any, line string) error {
+	eq := findAssign(line)
+	if eq < 0 {
+		return fmt.Errorf("missing '=' in %q", line)
+	}
+	keys, err := ParsePath(strings.TrimSpace(line[:eq]))
+	if err != nil {
+		return err
+	}
+	value, err := ParseLiteral(strings.TrimSpace(line[eq+1:]), "")
+	if err != nil {
+		return err
+	}
+	return SetValue(data, keys, value)
+}
+
+// findAssign 查找引号之外的第一个'='
+func findAssign(line string) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '=':
+			return i
+		}
+	}
+	return -1
+}
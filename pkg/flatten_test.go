@@ -0,0 +1,50 @@
+package pkg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlattenRoundTrip(t *testing.T) {
+	src := `
+title = "x"
+"a.b" = 1
+'say "hi"' = true
+"x=y" = "z"
+
+[servers."eu=1"]
+ports = [80, 443]
+
+[[items]]
+name = "a"
+
+[[items]]
+name = "b"
+tags = []
+`
+	data, err := DecodeToml(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, entry := range Flatten(data) {
+		literal, err := TomlLiteral(entry.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, FormatPath(entry.Path)+" = "+literal)
+	}
+	back, err := Unflatten(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatalf("Unflatten error: %v\n%s", err, strings.Join(lines, "\n"))
+	}
+	want, _ := EncodeToml(data)
+	got, _ := EncodeToml(back)
+	if got != want {
+		t.Fatalf("round trip mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	if !reflect.DeepEqual(SortedKeys(back), SortedKeys(data)) {
+		t.Fatalf("keys = %v, want %v", SortedKeys(back), SortedKeys(data))
+	}
+}
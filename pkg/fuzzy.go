@@ -0,0 +1,34 @@
+package pkg
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// FuzzyScore 判断pattern中的字符是否按顺序出现在text中（忽略大小写），
+// 匹配时返回分数，连续匹配和在分隔符之后匹配的分数更高，越短的text分数越高
+func FuzzyScore(pattern, text string) (int, bool) {
+	if len(pattern) == 0 {
+		return 0, true
+	}
+	p := []rune(strings.ToLower(pattern))
+	score, consecutive, pi := 0, 0, 0
+	prev := rune(0)
+	for _, r := range strings.ToLower(text) {
+		if pi < len(p) && r == p[pi] {
+			pi++
+			consecutive++
+			score += consecutive * 2
+			if prev == 0 || prev == '.' || prev == '_' || prev == '-' || prev == '[' {
+				score += 3
+			}
+		} else {
+			consecutive = 0
+		}
+		prev = r
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	return score*10 - utf8.RuneCountInString(text), true
+}
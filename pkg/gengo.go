@@ -0,0 +1,212 @@
+package pkg
+
+import (
+	"fmt"
+	"go/format"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoOptions 生成go代码的选项
+type GoOptions struct {
+	Package  string   // 包名
+	TypeName string   // 根结构体的名称
+	Tags     []string // 字段的tag，如 toml、json、yaml
+	Pointers bool     // 可选字段使用指针类型
+}
+
+// GenerateGo 根据schema生成go的类型声明，表生成结构体，不是所有示例中都出现的字段带有omitempty
+func GenerateGo(s *Schema, opts GoOptions) (string, error) {
+	g := &goGenerator{opts: opts, used: map[string]bool{}}
+	g.queue = append(g.queue, goStruct{name: g.typeName("", opts.TypeName), schema: s})
+	for i := 0; i < len(g.queue); i++ {
+		g.writeStruct(g.queue[i])
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by aq gen-go. DO NOT EDIT.\n\n")
+	sb.WriteString("package " + opts.Package + "\n\n")
+	if g.usesTime {
+		sb.WriteString("import \"time\"\n\n")
+	}
+	sb.WriteString(g.body.String())
+	out, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return "", fmt.Errorf("format generated code: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// goStruct 待生成的结构体
+type goStruct struct {
+	name   string
+	schema *Schema
+}
+
+type goGenerator struct {
+	opts     GoOptions
+	queue    []goStruct
+	used     map[string]bool // 已经使用的类型名
+	usesTime bool
+	body     strings.Builder
+}
+
+func (g *goGenerator) writeStruct(st goStruct) {
+	fmt.Fprintf(&g.body, "type %s struct {\n", st.name)
+	required := map[string]bool{}
+	for _, k := range st.schema.Required {
+		required[k] = true
+	}
+	fields := map[string]bool{}
+	for _, k := range schemaKeys(st.schema.Properties) {
+		field := uniqueName(fields, GoName(k))
+		fields[field] = true
+		typ := g.goType(st.name, k, st.schema.Properties[k])
+		if !required[k] && g.opts.Pointers && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") && typ != "any" && !strings.HasPrefix(typ, "*") {
+			typ = "*" + typ
+		}
+		fmt.Fprintf(&g.body, "\t%s %s %s\n", field, typ, g.tag(k, !required[k]))
+	}
+	g.body.WriteString("}\n\n")
+}
+
+// goType schema对应的go类型，需要新结构体时加入队列
+func (g *goGenerator) goType(parent, key string, s *Schema) string {
+	if s == nil {
+		return "any"
+	}
+	// 可能为null的值使用指针
+	types := slices.DeleteFunc(slices.Clone(s.Type), func(t string) bool { return t == "null" })
+	if len(types) == 1 && len(types) < len(s.Type) {
+		nonNull := *s
+		nonNull.Type = types
+		typ := g.goType(parent, key, &nonNull)
+		if strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "any" {
+			return typ
+		}
+		return "*" + typ
+	}
+	if len(s.Type) != 1 {
+		return "any"
+	}
+	switch s.Type[0] {
+	case "string":
+		switch s.Format {
+		case "date-time", "date", "time", "local-date-time":
+			g.usesTime = true
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		if s.Items == nil || len(s.Items.Type) == 0 {
+			return "[]any"
+		}
+		return "[]" + g.goType(parent, singular(key), s.Items)
+	case "object":
+		if len(s.Properties) == 0 {
+			return "map[string]any"
+		}
+		name := g.typeName(parent, GoName(key))
+		g.queue = append(g.queue, goStruct{name: name, schema: s})
+		return name
+	}
+	return "any"
+}
+
+// typeName 分配不重复的类型名，重名时加上父结构体的名称
+func (g *goGenerator) typeName(parent, name string) string {
+	if g.used[name] {
+		name = parent + name
+	}
+	name = uniqueName(g.used, name)
+	g.used[name] = true
+	return name
+}
+
+func (g *goGenerator) tag(key string, optional bool) string {
+	if len(g.opts.Tags) == 0 {
+		return ""
+	}
+	value := key
+	if optional {
+		value += ",omitempty"
+	}
+	parts := make([]string, len(g.opts.Tags))
+	for i, t := range g.opts.Tags {
+		parts[i] = t + ":" + strconv.Quote(value)
+	}
+	return "`" + strings.Join(parts, " ") + "`"
+}
+
+func schemaKeys(props map[string]*Schema) []string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// uniqueName 名称已被使用时加上数字后缀
+func uniqueName(used map[string]bool, name string) string {
+	if !used[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		if n := name + strconv.Itoa(i); !used[n] {
+			return n
+		}
+	}
+}
+
+// goInitialisms 按go的命名习惯全部大写的缩写
+var goInitialisms = map[string]bool{
+	"api": true, "cpu": true, "dns": true, "html": true, "http": true, "https": true, "id": true, "ip": true,
+	"json": true, "sql": true, "ssh": true, "tcp": true, "tls": true, "toml": true, "ttl": true, "udp": true,
+	"ui": true, "uri": true, "url": true, "uuid": true, "xml": true, "yaml": true,
+}
+
+// GoName 将key转换为导出的go标识符，如 max_conns -> MaxConns、base-url -> BaseURL
+func GoName(key string) string {
+	parts := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var sb strings.Builder
+	for _, part := range parts {
+		if goInitialisms[strings.ToLower(part)] {
+			sb.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+	name := sb.String()
+	if len(name) == 0 {
+		return "Field"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// singular 数组元素的类型名使用单数形式，如 servers -> server
+func singular(key string) string {
+	switch {
+	case strings.HasSuffix(key, "ies") && len(key) > 3:
+		return key[:len(key)-3] + "y"
+	case strings.HasSuffix(key, "s") && !strings.HasSuffix(key, "ss") && !strings.HasSuffix(key, "us") && !strings.HasSuffix(key, "is") && len(key) > 1:
+		return key[:len(key)-1]
+	}
+	return key
+}
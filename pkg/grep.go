@@ -0,0 +1,26 @@
+package pkg
+
+import "regexp"
+
+// GrepMatch 搜索命中的节点
+type GrepMatch struct {
+	Path  []PathKey
+	Value any
+}
+
+// Grep 用正则匹配文档中的key路径和标量值，keys和values控制匹配哪一部分
+func Grep(data map[string]any, re *regexp.Regexp, keys, values bool) []GrepMatch {
+	var matches []GrepMatch
+	Walk(data, func(path []PathKey, value any) bool {
+		hit := keys && re.MatchString(FormatPath(path))
+		if !hit && values && IsScalar(value) {
+			text, err := FormatValue(value)
+			hit = err == nil && re.MatchString(text)
+		}
+		if hit {
+			matches = append(matches, GrepMatch{Path: path, Value: value})
+		}
+		return true
+	})
+	return matches
+}
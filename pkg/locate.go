@@ -0,0 +1,145 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// KeyLines 记录每个key路径在源文件中定义的行号
+type KeyLines map[string]int
+
+// LocateKeys 扫描toml源文本，记录表头和键值对所在的行，用于在错误信息中给出位置。
+// 内联表和数组内部的key不单独记录，查找时会退回到外层key所在的行
+func LocateKeys(src []byte) KeyLines {
+	lines := KeyLines{}
+	arrays := map[string]int{} // 数组表的路径 -> 已出现的元素个数
+	var current []PathKey
+	var multiline string // 正在跳过的多行字符串的结束符
+
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	scanner.Buffer(make([]byte, 64*1024), len(src)+1)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		text := scanner.Text()
+		if len(multiline) > 0 {
+			if strings.Contains(text, multiline) {
+				multiline = ""
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(text)
+		if len(trimmed) == 0 || trimmed[0] == '#' {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[[") {
+			if end := strings.Index(trimmed, "]]"); end > 0 {
+				path := resolveHeader(splitTomlKey(trimmed[2:end]), arrays)
+				id := FormatPath(path)
+				path = append(path, PathKey{Index: arrays[id], IsIndex: true})
+				arrays[id]++
+				current = path
+				lines.set(path, lineNo)
+				lines.setIfMissing(path[:len(path)-1], lineNo)
+			}
+			continue
+		}
+		if trimmed[0] == '[' {
+			if end := strings.IndexByte(trimmed, ']'); end > 0 {
+				current = resolveHeader(splitTomlKey(trimmed[1:end]), arrays)
+				lines.set(current, lineNo)
+			}
+			continue
+		}
+
+		eq := findAssign(trimmed)
+		if eq < 0 {
+			continue
+		}
+		path := append(append([]PathKey(nil), current...), splitTomlKey(trimmed[:eq])...)
+		lines.set(path, lineNo)
+		value := strings.TrimSpace(trimmed[eq+1:])
+		for _, quote := range []string{`"""`, `'''`} {
+			if strings.HasPrefix(value, quote) && !strings.Contains(value[3:], quote) {
+				multiline = quote
+			}
+		}
+	}
+	return lines
+}
+
+// Line 返回路径所在的行，路径本身没有记录时使用最近的上层路径，都找不到时返回0
+func (kl KeyLines) Line(path []PathKey) int {
+	for i := len(path); i > 0; i-- {
+		if line, ok := kl[FormatPath(path[:i])]; ok {
+			return line
+		}
+	}
+	return 0
+}
+
+func (kl KeyLines) set(path []PathKey, line int) {
+	kl[FormatPath(path)] = line
+}
+
+func (kl KeyLines) setIfMissing(path []PathKey, line int) {
+	if _, ok := kl[FormatPath(path)]; !ok {
+		kl.set(path, line)
+	}
+}
+
+// resolveHeader 表头中指向数组表的部分使用其最后一个元素，如 [servers.tls] 对应 servers[N].tls
+func resolveHeader(keys []PathKey, arrays map[string]int) []PathKey {
+	var path []PathKey
+	for _, k := range keys {
+		path = append(path, k)
+		if n, ok := arrays[FormatPath(path)]; ok && n > 0 {
+			path = append(path, PathKey{Index: n - 1, IsIndex: true})
+		}
+	}
+	return path
+}
+
+// splitTomlKey 拆分点分key，支持带引号的部分以及点号两侧的空白
+func splitTomlKey(key string) []PathKey {
+	var keys []PathKey
+	for len(key) > 0 {
+		key = strings.TrimSpace(key)
+		var part string
+		switch {
+		case strings.HasPrefix(key, `"`):
+			end := 1
+			for end < len(key) && key[end] != '"' {
+				if key[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(key) {
+				return append(keys, PathKey{Key: key})
+			}
+			unquoted, err := strconv.Unquote(key[:end+1])
+			if err != nil {
+				unquoted = key[1:end]
+			}
+			part, key = unquoted, key[end+1:]
+		case strings.HasPrefix(key, "'"):
+			end := strings.IndexByte(key[1:], '\'')
+			if end < 0 {
+				return append(keys, PathKey{Key: key})
+			}
+			part, key = key[1:end+1], key[end+2:]
+		default:
+			end := strings.IndexByte(key, '.')
+			if end < 0 {
+				end = len(key)
+			}
+			part, key = strings.TrimSpace(key[:end]), key[end:]
+		}
+		keys = append(keys, PathKey{Key: part})
+		key = strings.TrimSpace(key)
+		key = strings.TrimPrefix(key, ".")
+	}
+	return keys
+}
@@ -0,0 +1,43 @@
+package pkg
+
+import "fmt"
+
+// 合并数组时的策略
+const (
+	ArraysReplace = "replace"
+	ArraysAppend  = "append"
+)
+
+// Merge 将src合并到dst中，表递归合并，其余值由src覆盖，数组按照arrays策略处理
+func Merge(dst, src map[string]any, arrays string) error {
+	if arrays != ArraysReplace && arrays != ArraysAppend {
+		return fmt.Errorf("unknown array strategy %q, want one of append|replace", arrays)
+	}
+	mergeTable(dst, src, arrays)
+	return nil
+}
+
+func mergeTable(dst, src map[string]any, arrays string) {
+	for k, sv := range src {
+		dv, exist := dst[k]
+		if !exist {
+			dst[k] = sv
+			continue
+		}
+		if dt, ok := dv.(map[string]any); ok {
+			if st, ok := sv.(map[string]any); ok {
+				mergeTable(dt, st, arrays)
+				continue
+			}
+		}
+		if arrays == ArraysAppend {
+			if da, ok := toArray(dv); ok {
+				if sa, ok := toArray(sv); ok {
+					dst[k] = fromArray(append(da, sa...))
+					continue
+				}
+			}
+		}
+		dst[k] = sv
+	}
+}
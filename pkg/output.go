@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 支持的输出格式
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+	FormatRaw  = "raw"
+)
+
+// 时间在json和yaml中的输出方式
+const (
+	DatetimeString = "string" // toml中的文本形式，如 1979-05-27T07:32:00Z
+	DatetimeUnix   = "unix"   // 带时区的时间输出为unix秒，本地时间仍然输出文本
+	DatetimeTagged = "tagged" // 输出为 {"type": "...", "value": "..."}，保留时间类型
+)
+
+// RenderOptions 渲染时的可选项
+type RenderOptions struct {
+	Compact  bool   // json输出为单行
+	Datetime string // 时间的输出方式，为空时等同于DatetimeString
+}
+
+// RenderValue 按照指定格式渲染结果
+func RenderValue(value any, format string) (string, error) {
+	return RenderValueWith(value, format, RenderOptions{})
+}
+
+// RenderValueWith 按照指定格式和选项渲染结果
+func RenderValueWith(value any, format string, opts RenderOptions) (string, error) {
+	switch opts.Datetime {
+	case "", DatetimeString, DatetimeUnix, DatetimeTagged:
+	default:
+		return "", fmt.Errorf("unknown datetime mode %q, want one of string|unix|tagged", opts.Datetime)
+	}
+
+	switch format {
+	case FormatRaw:
+		return FormatValue(value)
+	case FormatJSON:
+		var raw []byte
+		var err error
+		if opts.Compact {
+			raw, err = json.Marshal(normalizeValue(value, opts.Datetime))
+		} else {
+			raw, err = json.MarshalIndent(normalizeValue(value, opts.Datetime), "", "  ")
+		}
+		if err != nil {
+			return "", err
+		}
+		return string(raw), nil
+	case FormatYAML:
+		var sb strings.Builder
+		enc := yaml.NewEncoder(&sb)
+		enc.SetIndent(2)
+		if err := enc.Encode(normalizeValue(value, opts.Datetime)); err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(sb.String(), "\n"), nil
+	case FormatTOML:
+		if table, ok := value.(map[string]any); ok {
+			return EncodeToml(table)
+		}
+		// 非表的值输出为toml字面量，表的数组输出为内联表的数组
+		return TomlLiteral(value)
+	case FormatTable, FormatMarkdown, FormatCSV:
+		return RenderTabular(value, format)
+	}
+	return "", fmt.Errorf("unknown output format %q, want one of json|yaml|toml|raw|table|markdown|csv", format)
+}
+
+// TomlLiteral 将值渲染为单行的toml字面量，如 "x"、[1, 2]、{ a = 1 }，表和表的数组使用内联表
+func TomlLiteral(value any) (string, error) {
+	if table, ok := value.(map[string]any); ok {
+		if len(table) == 0 {
+			return "{}", nil
+		}
+		parts := make([]string, 0, len(table))
+		for _, k := range SortedKeys(table) {
+			literal, err := TomlLiteral(table[k])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, quoteKey(k)+" = "+literal)
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	}
+	if arr, ok := toArray(value); ok {
+		parts := make([]string, len(arr))
+		for i, item := range arr {
+			literal, err := TomlLiteral(item)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = literal
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	}
+
+	out, err := EncodeToml(map[string]any{"v": value})
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(out, "v = ") || strings.Contains(out, "\n") {
+		return "", fmt.Errorf("value of type %T cannot be rendered as a toml literal", value)
+	}
+	return strings.TrimPrefix(out, "v = "), nil
+}
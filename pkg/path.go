@@ -0,0 +1,171 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PathKey 路径中的一段，可能是表中的key，也可能是数组下标
+type PathKey struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+func (p PathKey) String() string {
+	if p.IsIndex {
+		return "[" + strconv.Itoa(p.Index) + "]"
+	}
+	return p.Key
+}
+
+// ParsePath 解析路径表达式，支持 servers[1].host 以及 "a.b".c 这样的引号key，引号的规则与toml的key相同
+func ParsePath(path string) ([]PathKey, error) {
+	var keys []PathKey
+	i := 0
+	expectKey := true
+	for i < len(path) {
+		c := path[i]
+		switch {
+		case c == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed '['", path)
+			}
+			idx, err := strconv.Atoi(path[i+1 : i+end])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("invalid path %q: bad index %q", path, path[i+1:i+end])
+			}
+			keys = append(keys, PathKey{Index: idx, IsIndex: true})
+			i += end + 1
+			expectKey = false
+		case c == '.':
+			if expectKey {
+				return nil, fmt.Errorf("invalid path %q: empty key at offset %d", path, i)
+			}
+			i++
+			expectKey = true
+		case c == '"' || c == '\'':
+			if !expectKey {
+				return nil, fmt.Errorf("invalid path %q: missing '.' at offset %d", path, i)
+			}
+			end := closingQuote(path[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed quote", path)
+			}
+			key, err := unquoteKey(path[i : i+end+2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", path, err)
+			}
+			keys = append(keys, PathKey{Key: key})
+			i += end + 2
+			expectKey = false
+		default:
+			if !expectKey {
+				return nil, fmt.Errorf("invalid path %q: missing '.' at offset %d", path, i)
+			}
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			keys = append(keys, PathKey{Key: path[i : i+end]})
+			i += end
+			expectKey = false
+		}
+	}
+	if expectKey && len(path) > 0 {
+		return nil, fmt.Errorf("invalid path %q: trailing '.'", path)
+	}
+	return keys, nil
+}
+
+// closingQuote 返回结束引号的位置，双引号中的反斜杠转义会被跳过
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteKey 按toml字符串的规则去掉key的引号并处理转义
+func unquoteKey(literal string) (string, error) {
+	var doc map[string]any
+	if _, err := toml.Decode("v = "+literal, &doc); err != nil {
+		return "", fmt.Errorf("bad quoted key %s", literal)
+	}
+	key, ok := doc["v"].(string)
+	if !ok {
+		return "", fmt.Errorf("bad quoted key %s", literal)
+	}
+	return key, nil
+}
+
+// FormatPath 将路径重新拼接为字符串，需要时按toml的规则给key加上引号，结果可以由ParsePath还原
+func FormatPath(keys []PathKey) string {
+	var sb strings.Builder
+	for i, k := range keys {
+		if k.IsIndex {
+			sb.WriteString(k.String())
+			continue
+		}
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(quoteKey(k.Key))
+	}
+	return sb.String()
+}
+
+// isBareKey 判断key是否可以不加引号
+func isBareKey(key string) bool {
+	if len(key) == 0 {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// Lookup 按照解析后的路径查找值
+func Lookup(data any, keys []PathKey) (any, bool) {
+	cur := data
+	for _, k := range keys {
+		if k.IsIndex {
+			switch arr := cur.(type) {
+			case []any:
+				if k.Index >= len(arr) {
+					return nil, false
+				}
+				cur = arr[k.Index]
+			case []map[string]any:
+				if k.Index >= len(arr) {
+					return nil, false
+				}
+				cur = arr[k.Index]
+			default:
+				return nil, false
+			}
+			continue
+		}
+		table, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		cur, ok = table[k.Key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatPathRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		path []PathKey
+		want string
+	}{
+		{"bare", []PathKey{{Key: "server"}, {Key: "port"}}, "server.port"},
+		{"index", []PathKey{{Key: "servers"}, {Index: 1, IsIndex: true}, {Key: "host"}}, "servers[1].host"},
+		{"dot", []PathKey{{Key: "a.b"}, {Key: "c"}}, `"a.b".c`},
+		{"double quote", []PathKey{{Key: `say "hi"`}}, `"say \"hi\""`},
+		{"single quote", []PathKey{{Key: "it's"}}, `"it's"`},
+		{"backslash", []PathKey{{Key: `C:\dir`}}, `"C:\\dir"`},
+		{"bracket", []PathKey{{Key: "a[0]"}}, `"a[0]"`},
+		{"empty", []PathKey{{Key: ""}}, `""`},
+		{"unicode", []PathKey{{Key: "名字"}}, `"名字"`},
+		{"control", []PathKey{{Key: "tab\there"}}, `"tab\there"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatPath(tt.path)
+			if got != tt.want {
+				t.Fatalf("FormatPath() = %s, want %s", got, tt.want)
+			}
+			parsed, err := ParsePath(got)
+			if err != nil {
+				t.Fatalf("ParsePath(%s) error: %v", got, err)
+			}
+			if !reflect.DeepEqual(parsed, tt.path) {
+				t.Fatalf("ParsePath(%s) = %v, want %v", got, parsed, tt.path)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []PathKey
+		wantErr bool
+	}{
+		{path: "", want: nil},
+		{path: "a", want: []PathKey{{Key: "a"}}},
+		{path: "a.b-c.d_e", want: []PathKey{{Key: "a"}, {Key: "b-c"}, {Key: "d_e"}}},
+		{path: "servers[0]", want: []PathKey{{Key: "servers"}, {Index: 0, IsIndex: true}}},
+		{path: "m[1][2].x", want: []PathKey{{Key: "m"}, {Index: 1, IsIndex: true}, {Index: 2, IsIndex: true}, {Key: "x"}}},
+		{path: `"a.b".c`, want: []PathKey{{Key: "a.b"}, {Key: "c"}}},
+		{path: `'a\b'.c`, want: []PathKey{{Key: `a\b`}, {Key: "c"}}},
+		{path: `"a\"b"`, want: []PathKey{{Key: `a"b`}}},
+		{path: `"é"`, want: []PathKey{{Key: "é"}}},
+		{path: "a.", wantErr: true},
+		{path: ".a", wantErr: true},
+		{path: "a..b", wantErr: true},
+		{path: "a[", wantErr: true},
+		{path: "a[x]", wantErr: true},
+		{path: "a[-1]", wantErr: true},
+		{path: `"a`, wantErr: true},
+		{path: `"a"b`, wantErr: true},
+		{path: `"\q"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParsePath(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParsePath(%s) = %v, want an error", tt.path, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePath(%s) error: %v", tt.path, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParsePath(%s) = %#v, want %#v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	data := map[string]any{
+		"servers": []map[string]any{{"host": "a"}, {"host": "b"}},
+		"ports":   []any{int64(80), int64(443)},
+		"a.b":     map[string]any{"c": true},
+	}
+	tests := []struct {
+		path   string
+		want   any
+		wantOK bool
+	}{
+		{"servers[1].host", "b", true},
+		{"ports[0]", int64(80), true},
+		{`"a.b".c`, true, true},
+		{"servers[2].host", nil, false},
+		{"ports.x", nil, false},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			keys, err := ParsePath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := Lookup(data, keys)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Lookup(%s) = %v, %t, want %v, %t", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
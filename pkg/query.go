@@ -0,0 +1,683 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query 编译后的jq风格查询表达式，支持字段访问、数组下标与遍历、select过滤以及管道
+type Query struct {
+	root queryNode
+}
+
+// queryNode 表达式节点，对一个输入产生零个或多个输出
+type queryNode interface {
+	eval(input any) ([]any, error)
+}
+
+// CompileQuery 解析查询表达式，如 .servers[] | select(.enabled) | .host
+func CompileQuery(expr string) (*Query, error) {
+	tokens, err := lexQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	node, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("query: unexpected %q", p.peek().text)
+	}
+	return &Query{root: node}, nil
+}
+
+// Run 对输入执行查询，返回所有输出
+func (q *Query) Run(input any) ([]any, error) {
+	return q.root.eval(input)
+}
+
+// ---------- 词法分析 ----------
+
+const (
+	tokEOF = iota
+	tokDot
+	tokIdent
+	tokString
+	tokNumber
+	tokPunct
+)
+
+type queryToken struct {
+	kind int
+	text string
+}
+
+func lexQuery(expr string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '.':
+			tokens = append(tokens, queryToken{kind: tokDot, text: "."})
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(expr) && expr[j] != '"' {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("query: unterminated string")
+			}
+			s, err := strconv.Unquote(expr[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("query: invalid string %s", expr[i:j+1])
+			}
+			tokens = append(tokens, queryToken{kind: tokString, text: s})
+			i = j + 1
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9':
+			j := i + 1
+			for j < len(expr) && (expr[j] >= '0' && expr[j] <= '9' || expr[j] == '.' || expr[j] == 'e' || expr[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, queryToken{kind: tokNumber, text: expr[i:j]})
+			i = j
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i + 1
+			for j < len(expr) && (expr[j] == '_' || expr[j] == '-' || expr[j] >= 'a' && expr[j] <= 'z' || expr[j] >= 'A' && expr[j] <= 'Z' || expr[j] >= '0' && expr[j] <= '9') {
+				j++
+			}
+			tokens = append(tokens, queryToken{kind: tokIdent, text: expr[i:j]})
+			i = j
+		default:
+			op := matchPunct(expr[i:])
+			if len(op) == 0 {
+				return nil, fmt.Errorf("query: unexpected character %q at offset %d", c, i)
+			}
+			tokens = append(tokens, queryToken{kind: tokPunct, text: op})
+			i += len(op)
+		}
+	}
+	return append(tokens, queryToken{kind: tokEOF}), nil
+}
+
+// matchPunct 匹配运算符和括号，较长的运算符优先
+func matchPunct(s string) string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "|", "[", "]", "(", ")", "<", ">"} {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// ---------- 语法分析 ----------
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *queryParser) accept(kind int, text string) bool {
+	t := p.peek()
+	if t.kind == kind && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) expect(kind int, text string) error {
+	if !p.accept(kind, text) {
+		return fmt.Errorf("query: expected %q but found %q", text, p.peek().text)
+	}
+	return nil
+}
+
+func (p *queryParser) parsePipe() (queryNode, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokPunct, "|") {
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = pipeNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokIdent, "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicNode{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokIdent, "and") {
+		right, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		left = logicNode{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseCompare() (queryNode, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind == tokPunct {
+		switch t.text {
+		case "==", "!=", "<", "<=", ">", ">=":
+			p.next()
+			right, err := p.parsePostfix()
+			if err != nil {
+				return nil, err
+			}
+			return compareNode{op: t.text, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parsePostfix() (queryNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.peek().kind == tokDot:
+			p.next()
+			field, err := p.parseField()
+			if err != nil {
+				return nil, err
+			}
+			node = pipeNode{left: node, right: field}
+		case p.peek().kind == tokPunct && p.peek().text == "[":
+			suffix, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			node = pipeNode{left: node, right: suffix}
+		default:
+			return node, nil
+		}
+	}
+}
+
+// parseField 解析点号后面的字段名
+func (p *queryParser) parseField() (queryNode, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokIdent, tokString:
+		p.next()
+		return fieldNode{key: t.text}, nil
+	case tokPunct:
+		if t.text == "[" {
+			return p.parseBracket()
+		}
+	}
+	return nil, fmt.Errorf("query: expected field name after '.' but found %q", t.text)
+}
+
+// parseBracket 解析 [] [n] ["key"]
+func (p *queryParser) parseBracket() (queryNode, error) {
+	if err := p.expect(tokPunct, "["); err != nil {
+		return nil, err
+	}
+	if p.accept(tokPunct, "]") {
+		return iterateNode{}, nil
+	}
+	t := p.next()
+	var node queryNode
+	switch t.kind {
+	case tokNumber:
+		idx, err := strconv.Atoi(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("query: invalid index %q", t.text)
+		}
+		node = indexNode{index: idx}
+	case tokString:
+		node = fieldNode{key: t.text}
+	default:
+		return nil, fmt.Errorf("query: expected index or string in brackets but found %q", t.text)
+	}
+	if err := p.expect(tokPunct, "]"); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokDot:
+		p.next()
+		next := p.peek()
+		if next.kind == tokIdent || next.kind == tokString || next.kind == tokPunct && next.text == "[" {
+			return p.parseField()
+		}
+		return identityNode{}, nil
+	case tokString:
+		p.next()
+		return literalNode{value: t.text}, nil
+	case tokNumber:
+		p.next()
+		if n, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return literalNode{value: n}, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("query: invalid number %q", t.text)
+		}
+		return literalNode{value: f}, nil
+	case tokPunct:
+		if t.text == "(" {
+			p.next()
+			node, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokPunct, ")"); err != nil {
+				return nil, err
+			}
+			return node, nil
+		}
+	case tokIdent:
+		p.next()
+		switch t.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{value: nil}, nil
+		case "not", "length", "keys":
+			return funcNode{name: t.text}, nil
+		case "select":
+			if err := p.expect(tokPunct, "("); err != nil {
+				return nil, err
+			}
+			cond, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokPunct, ")"); err != nil {
+				return nil, err
+			}
+			return selectNode{cond: cond}, nil
+		}
+		return nil, fmt.Errorf("query: unknown function %q", t.text)
+	}
+	return nil, fmt.Errorf("query: unexpected %q", t.text)
+}
+
+// ---------- 求值 ----------
+
+type identityNode struct{}
+
+func (identityNode) eval(input any) ([]any, error) {
+	return []any{input}, nil
+}
+
+type literalNode struct {
+	value any
+}
+
+func (n literalNode) eval(input any) ([]any, error) {
+	return []any{n.value}, nil
+}
+
+type fieldNode struct {
+	key string
+}
+
+func (n fieldNode) eval(input any) ([]any, error) {
+	switch v := input.(type) {
+	case nil:
+		return []any{nil}, nil
+	case map[string]any:
+		return []any{v[n.key]}, nil
+	}
+	return nil, fmt.Errorf("query: cannot index %s with %q", TypeName(input), n.key)
+}
+
+type indexNode struct {
+	index int
+}
+
+func (n indexNode) eval(input any) ([]any, error) {
+	if input == nil {
+		return []any{nil}, nil
+	}
+	arr, ok := toArray(input)
+	if !ok {
+		return nil, fmt.Errorf("query: cannot index %s with number", TypeName(input))
+	}
+	idx := n.index
+	if idx < 0 {
+		idx += len(arr)
+	}
+	if idx < 0 || idx >= len(arr) {
+		return []any{nil}, nil
+	}
+	return []any{arr[idx]}, nil
+}
+
+type iterateNode struct{}
+
+func (iterateNode) eval(input any) ([]any, error) {
+	if table, ok := input.(map[string]any); ok {
+		out := make([]any, 0, len(table))
+		for _, k := range SortedKeys(table) {
+			out = append(out, table[k])
+		}
+		return out, nil
+	}
+	arr, ok := toArray(input)
+	if !ok {
+		return nil, fmt.Errorf("query: cannot iterate over %s", typeNameOrNull(input))
+	}
+	return arr, nil
+}
+
+type pipeNode struct {
+	left, right queryNode
+}
+
+func (n pipeNode) eval(input any) ([]any, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	for _, v := range lefts {
+		rights, err := n.right.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rights...)
+	}
+	return out, nil
+}
+
+type selectNode struct {
+	cond queryNode
+}
+
+func (n selectNode) eval(input any) ([]any, error) {
+	conds, err := n.cond.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	for _, c := range conds {
+		if truthy(c) {
+			out = append(out, input)
+		}
+	}
+	return out, nil
+}
+
+type logicNode struct {
+	op          string
+	left, right queryNode
+}
+
+func (n logicNode) eval(input any) ([]any, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	for _, l := range lefts {
+		// 短路求值
+		if n.op == "or" && truthy(l) || n.op == "and" && !truthy(l) {
+			out = append(out, truthy(l))
+			continue
+		}
+		rights, err := n.right.eval(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rights {
+			out = append(out, truthy(r))
+		}
+	}
+	return out, nil
+}
+
+type compareNode struct {
+	op          string
+	left, right queryNode
+}
+
+func (n compareNode) eval(input any) ([]any, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := n.right.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	for _, r := range rights {
+		for _, l := range lefts {
+			c, err := compareValues(l, r)
+			if err != nil {
+				if n.op == "==" || n.op == "!=" {
+					// 不同类型的值只是不相等
+					out = append(out, n.op == "!=")
+					continue
+				}
+				return nil, err
+			}
+			switch n.op {
+			case "==":
+				out = append(out, c == 0)
+			case "!=":
+				out = append(out, c != 0)
+			case "<":
+				out = append(out, c < 0)
+			case "<=":
+				out = append(out, c <= 0)
+			case ">":
+				out = append(out, c > 0)
+			case ">=":
+				out = append(out, c >= 0)
+			}
+		}
+	}
+	return out, nil
+}
+
+type funcNode struct {
+	name string
+}
+
+func (n funcNode) eval(input any) ([]any, error) {
+	switch n.name {
+	case "not":
+		return []any{!truthy(input)}, nil
+	case "length":
+		switch v := input.(type) {
+		case nil:
+			return []any{int64(0)}, nil
+		case string:
+			return []any{int64(len([]rune(v)))}, nil
+		case map[string]any:
+			return []any{int64(len(v))}, nil
+		}
+		if arr, ok := toArray(input); ok {
+			return []any{int64(len(arr))}, nil
+		}
+		return nil, fmt.Errorf("query: %s has no length", TypeName(input))
+	case "keys":
+		table, ok := input.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("query: %s has no keys", typeNameOrNull(input))
+		}
+		keys := SortedKeys(table)
+		out := make([]any, len(keys))
+		for i, k := range keys {
+			out[i] = k
+		}
+		return []any{out}, nil
+	}
+	return nil, fmt.Errorf("query: unknown function %q", n.name)
+}
+
+// truthy false和null为假，其余为真
+func truthy(v any) bool {
+	switch b := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return b
+	}
+	return true
+}
+
+func typeNameOrNull(v any) string {
+	if v == nil {
+		return "null"
+	}
+	return TypeName(v)
+}
+
+// compareValues 比较两个标量，整数和浮点数可以互相比较
+func compareValues(a, b any) (int, error) {
+	if a == nil || b == nil {
+		if a == nil && b == nil {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("query: cannot compare %s with %s", typeNameOrNull(a), typeNameOrNull(b))
+	}
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			if ia, ok := a.(int64); ok {
+				if ib, ok := b.(int64); ok {
+					return compareOrdered(ia, ib), nil
+				}
+			}
+			return compareOrdered(fa, fb), nil
+		}
+	}
+	switch va := a.(type) {
+	case string:
+		if vb, ok := b.(string); ok {
+			return strings.Compare(va, vb), nil
+		}
+	case bool:
+		if vb, ok := b.(bool); ok {
+			if va == vb {
+				return 0, nil
+			}
+			if !va {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		if vb, ok := b.(time.Time); ok {
+			return va.Compare(vb), nil
+		}
+	}
+	if !IsScalar(a) || !IsScalar(b) {
+		if EqualDeep(a, b) {
+			return 0, nil
+		}
+	}
+	return 0, fmt.Errorf("query: cannot compare %s with %s", TypeName(a), TypeName(b))
+}
+
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// EqualDeep 递归比较两个值是否相等
+func EqualDeep(a, b any) bool {
+	if ta, ok := a.(map[string]any); ok {
+		tb, ok := b.(map[string]any)
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for k, va := range ta {
+			vb, ok := tb[k]
+			if !ok || !EqualDeep(va, vb) {
+				return false
+			}
+		}
+		return true
+	}
+	if aa, ok := toArray(a); ok {
+		ab, ok := toArray(b)
+		if !ok || len(aa) != len(ab) {
+			return false
+		}
+		for i := range aa {
+			if !EqualDeep(aa[i], ab[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return EqualValue(a, b)
+}
@@ -0,0 +1,111 @@
+package pkg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const queryDoc = `
+name = "app"
+tags = ["a", "b"]
+
+[[servers]]
+host = "alpha"
+port = 80
+enabled = true
+
+[[servers]]
+host = "beta"
+port = 8080
+enabled = false
+
+[owner]
+"full.name" = "Tom"
+`
+
+func TestQuery(t *testing.T) {
+	data, err := DecodeToml(strings.NewReader(queryDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expr string
+		want []any
+	}{
+		{".", []any{data}},
+		{".name", []any{"app"}},
+		{".missing", []any{nil}},
+		{".tags[1]", []any{"b"}},
+		{".tags[]", []any{"a", "b"}},
+		{".servers[0].host", []any{"alpha"}},
+		{".servers[].port", []any{int64(80), int64(8080)}},
+		{".servers[] | select(.enabled) | .host", []any{"alpha"}},
+		{".servers[] | select(.port > 100) | .host", []any{"beta"}},
+		{".servers[] | select(.port >= 80 and .enabled == false) | .host", []any{"beta"}},
+		{".servers[] | select(.host == \"alpha\" or .port == 8080) | .port", []any{int64(80), int64(8080)}},
+		{".servers[] | select(.enabled | not) | .host", []any{"beta"}},
+		{".servers | length", []any{int64(2)}},
+		{".name | length", []any{int64(3)}},
+		{".owner | keys", []any{[]any{"full.name"}}},
+		{".owner.\"full.name\"", []any{"Tom"}},
+		{".name != \"app\"", []any{false}},
+		{"true", []any{true}},
+		{"null", []any{nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			q, err := CompileQuery(tt.expr)
+			if err != nil {
+				t.Fatalf("CompileQuery(%s) error: %v", tt.expr, err)
+			}
+			got, err := q.Run(data)
+			if err != nil {
+				t.Fatalf("Run(%s) error: %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Run(%s) = %#v, want %#v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	data, err := DecodeToml(strings.NewReader(queryDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	compileErrors := []string{
+		".servers[",
+		".servers | ",
+		"select(.a",
+		".a ==",
+		"unknown",
+		".a )",
+	}
+	for _, expr := range compileErrors {
+		t.Run("compile "+expr, func(t *testing.T) {
+			if _, err := CompileQuery(expr); err == nil {
+				t.Fatalf("CompileQuery(%s) succeeded, want an error", expr)
+			}
+		})
+	}
+
+	runErrors := []string{
+		".name[0]",
+		".name[]",
+		".servers.host",
+		".servers[0].port | keys",
+	}
+	for _, expr := range runErrors {
+		t.Run("run "+expr, func(t *testing.T) {
+			q, err := CompileQuery(expr)
+			if err != nil {
+				t.Fatalf("CompileQuery(%s) error: %v", expr, err)
+			}
+			if _, err := q.Run(data); err == nil {
+				t.Fatalf("Run(%s) succeeded, want an error", expr)
+			}
+		})
+	}
+}
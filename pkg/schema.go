@@ -0,0 +1,225 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// SchemaDraft 生成的JSON Schema使用的版本
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema JSON Schema（draft 2020-12）中本工具支持的子集
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 SchemaTypes        `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+
+	// Bool 为true或false时表示布尔形式的schema，如 "additionalProperties": false
+	Bool *bool `json:"-"`
+}
+
+// SchemaTypes JSON Schema中的type，可以是单个字符串也可以是数组
+type SchemaTypes []string
+
+func (t SchemaTypes) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *SchemaTypes) UnmarshalJSON(raw []byte) error {
+	var one string
+	if err := json.Unmarshal(raw, &one); err == nil {
+		*t = SchemaTypes{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(raw, &many); err != nil {
+		return fmt.Errorf("schema type must be a string or an array of strings")
+	}
+	*t = many
+	return nil
+}
+
+type schemaAlias Schema
+
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.Bool != nil {
+		return json.Marshal(*s.Bool)
+	}
+	return json.Marshal((*schemaAlias)(s))
+}
+
+func (s *Schema) UnmarshalJSON(raw []byte) error {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		*s = Schema{Bool: &b}
+		return nil
+	}
+	return json.Unmarshal(raw, (*schemaAlias)(s))
+}
+
+// ---------- 推断 ----------
+
+// SchemaInferrer 从一个或多个示例文档推断schema
+type SchemaInferrer struct {
+	// MaxEnum 字符串字段的不同取值不超过该数量时输出enum，0表示不输出
+	MaxEnum int
+	root    *inferNode
+}
+
+// inferNode 推断过程中记录的某个路径上观察到的信息
+type inferNode struct {
+	count   int                   // 观察到的次数
+	types   map[string]bool       // 观察到的toml类型
+	objects int                   // 作为table出现的次数
+	props   map[string]*inferNode // table的子字段
+	items   *inferNode            // 数组元素
+	values  map[string]bool       // 观察到的字符串取值
+}
+
+func newInferNode() *inferNode {
+	return &inferNode{types: map[string]bool{}, props: map[string]*inferNode{}, values: map[string]bool{}}
+}
+
+// Observe 加入一个示例文档
+func (in *SchemaInferrer) Observe(data map[string]any) {
+	if in.root == nil {
+		in.root = newInferNode()
+	}
+	in.root.observe(data)
+}
+
+func (n *inferNode) observe(value any) {
+	n.count++
+	n.types[TypeName(value)] = true
+	switch v := value.(type) {
+	case map[string]any:
+		n.objects++
+		for k, item := range v {
+			child, ok := n.props[k]
+			if !ok {
+				child = newInferNode()
+				n.props[k] = child
+			}
+			child.observe(item)
+		}
+	case string:
+		n.values[v] = true
+	default:
+		if arr, ok := toArray(value); ok {
+			if n.items == nil {
+				n.items = newInferNode()
+			}
+			for _, item := range arr {
+				n.items.observe(item)
+			}
+		}
+	}
+}
+
+// Schema 返回推断出的schema，只在所有示例中都出现的字段才会列为required
+func (in *SchemaInferrer) Schema() *Schema {
+	if in.root == nil {
+		return &Schema{Schema: SchemaDraft, Type: SchemaTypes{"object"}}
+	}
+	s := in.root.schema(in.MaxEnum)
+	s.Schema = SchemaDraft
+	return s
+}
+
+func (n *inferNode) schema(maxEnum int) *Schema {
+	s := &Schema{}
+	typeSet := map[string]bool{}
+	for t := range n.types {
+		jsonType, format := jsonSchemaType(t)
+		typeSet[jsonType] = true
+		if len(format) > 0 {
+			s.Format = format
+		}
+	}
+	// integer是number的子集
+	if typeSet["integer"] && typeSet["number"] {
+		delete(typeSet, "integer")
+	}
+	for t := range typeSet {
+		s.Type = append(s.Type, t)
+	}
+	sort.Strings(s.Type)
+	// 混合了多种时间类型或者时间和普通字符串时不输出format
+	if len(n.types) > 1 {
+		s.Format = ""
+	}
+
+	if n.objects > 0 {
+		s.Properties = map[string]*Schema{}
+		for _, k := range sortedNodeKeys(n.props) {
+			child := n.props[k]
+			s.Properties[k] = child.schema(maxEnum)
+			if child.count == n.objects {
+				s.Required = append(s.Required, k)
+			}
+		}
+	}
+	if n.items != nil {
+		s.Items = n.items.schema(maxEnum)
+	}
+	if maxEnum > 0 && len(n.types) == 1 && n.types["string"] && len(n.values) <= maxEnum {
+		values := make([]string, 0, len(n.values))
+		for v := range n.values {
+			values = append(values, v)
+		}
+		sort.Strings(values)
+		for _, v := range values {
+			s.Enum = append(s.Enum, v)
+		}
+	}
+	return s
+}
+
+func sortedNodeKeys(m map[string]*inferNode) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonSchemaType toml类型对应的JSON Schema类型和format
+func jsonSchemaType(tomlType string) (string, string) {
+	switch tomlType {
+	case "table":
+		return "object", ""
+	case "float":
+		return "number", ""
+	case "datetime":
+		return "string", "date-time"
+	case "local-date":
+		return "string", "date"
+	case "local-time":
+		return "string", "time"
+	case "local-datetime":
+		return "string", "local-date-time"
+	}
+	return tomlType, ""
+}
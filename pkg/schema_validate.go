@@ -0,0 +1,208 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// Violation 文档中不满足schema的一处位置
+type Violation struct {
+	Path    []PathKey
+	Rule    string // 未通过的schema关键字，如 type、required
+	Message string
+}
+
+// ValidateSchema 按schema校验文档，返回全部的违反项
+func ValidateSchema(schema *Schema, data map[string]any) []Violation {
+	v := &schemaValidator{}
+	v.validate(schema, nil, data)
+	return v.violations
+}
+
+type schemaValidator struct {
+	violations []Violation
+}
+
+func (v *schemaValidator) fail(path []PathKey, rule, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *schemaValidator) validate(s *Schema, path []PathKey, value any) {
+	if s == nil {
+		return
+	}
+	if s.Bool != nil {
+		if !*s.Bool {
+			v.fail(path, "false", "no value is allowed here")
+		}
+		return
+	}
+
+	if len(s.Type) > 0 && !matchesAnyType(s.Type, value) {
+		v.fail(path, "type", "expected %s but found %s", joinTypes(s.Type), TypeName(value))
+		return
+	}
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		v.fail(path, "enum", "value %s is not one of %s", InlineValue(value), InlineValue(s.Enum))
+	}
+	if s.Const != nil && !schemaEqual(s.Const, value) {
+		v.fail(path, "const", "value must be %s", InlineValue(s.Const))
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateObject(s, path, val)
+	case string:
+		v.validateString(s, path, val)
+	case int64:
+		v.validateNumber(s, path, float64(val))
+	case float64:
+		v.validateNumber(s, path, val)
+	case time.Time:
+		v.validateFormat(s, path, val)
+	default:
+		if arr, ok := toArray(value); ok {
+			v.validateArray(s, path, arr)
+		}
+	}
+}
+
+func (v *schemaValidator) validateObject(s *Schema, path []PathKey, table map[string]any) {
+	for _, k := range s.Required {
+		if _, ok := table[k]; !ok {
+			v.fail(path, "required", "missing required key %q", k)
+		}
+	}
+	for _, k := range SortedKeys(table) {
+		sub := appendPath(path, PathKey{Key: k})
+		if prop, ok := s.Properties[k]; ok {
+			v.validate(prop, sub, table[k])
+			continue
+		}
+		if s.AdditionalProperties != nil {
+			if s.AdditionalProperties.Bool != nil && !*s.AdditionalProperties.Bool {
+				v.fail(sub, "additionalProperties", "unknown key %q", k)
+				continue
+			}
+			v.validate(s.AdditionalProperties, sub, table[k])
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(s *Schema, path []PathKey, arr []any) {
+	if s.MinItems != nil && len(arr) < *s.MinItems {
+		v.fail(path, "minItems", "array has %d items, want at least %d", len(arr), *s.MinItems)
+	}
+	if s.MaxItems != nil && len(arr) > *s.MaxItems {
+		v.fail(path, "maxItems", "array has %d items, want at most %d", len(arr), *s.MaxItems)
+	}
+	for i, item := range arr {
+		v.validate(s.Items, appendPath(path, PathKey{Index: i, IsIndex: true}), item)
+	}
+}
+
+func (v *schemaValidator) validateString(s *Schema, path []PathKey, str string) {
+	n := utf8.RuneCountInString(str)
+	if s.MinLength != nil && n < *s.MinLength {
+		v.fail(path, "minLength", "string has %d characters, want at least %d", n, *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		v.fail(path, "maxLength", "string has %d characters, want at most %d", n, *s.MaxLength)
+	}
+	if len(s.Pattern) > 0 {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			v.fail(path, "pattern", "invalid pattern %q in schema: %s", s.Pattern, err)
+		} else if !re.MatchString(str) {
+			v.fail(path, "pattern", "value %q does not match %q", str, s.Pattern)
+		}
+	}
+	layouts := map[string]string{"date-time": time.RFC3339, "date": time.DateOnly, "time": time.TimeOnly}
+	if layout, ok := layouts[s.Format]; ok {
+		if _, err := time.Parse(layout, str); err != nil {
+			v.fail(path, "format", "value %q is not a valid %s", str, s.Format)
+		}
+	}
+}
+
+func (v *schemaValidator) validateNumber(s *Schema, path []PathKey, n float64) {
+	if s.Minimum != nil && n < *s.Minimum {
+		v.fail(path, "minimum", "value %v is less than %v", n, *s.Minimum)
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		v.fail(path, "maximum", "value %v is greater than %v", n, *s.Maximum)
+	}
+}
+
+// validateFormat toml的时间值需要与format描述的时间类型一致
+func (v *schemaValidator) validateFormat(s *Schema, path []PathKey, t time.Time) {
+	want := map[string]string{"date-time": "datetime", "date": "local-date", "time": "local-time", "local-date-time": "local-datetime"}
+	if typ, ok := want[s.Format]; ok && TypeName(t) != typ {
+		v.fail(path, "format", "expected a %s but found a %s", s.Format, TypeName(t))
+	}
+}
+
+// matchesAnyType 时间值视为string，整数同时满足integer和number
+func matchesAnyType(types []string, value any) bool {
+	actual := TypeName(value)
+	for _, t := range types {
+		switch t {
+		case "object":
+			if actual == "table" {
+				return true
+			}
+		case "number":
+			if actual == "integer" || actual == "float" {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+			if _, ok := value.(time.Time); ok {
+				return true
+			}
+		case actual:
+			return true
+		}
+	}
+	return false
+}
+
+func joinTypes(types []string) string {
+	sorted := append([]string(nil), types...)
+	sort.Strings(sorted)
+	out := sorted[0]
+	for _, t := range sorted[1:] {
+		out += " or " + t
+	}
+	return out
+}
+
+func containsValue(list []any, value any) bool {
+	for _, item := range list {
+		if schemaEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaEqual 比较schema中的json值与文档中的值，json数字统一为float64
+func schemaEqual(schemaValue, value any) bool {
+	switch sv := schemaValue.(type) {
+	case float64:
+		if n, ok := toFloat(value); ok {
+			return sv == n
+		}
+		return false
+	case string:
+		if t, ok := value.(time.Time); ok {
+			return formatTime(t) == sv
+		}
+	}
+	return EqualDeep(schemaValue, NormalizeValue(value))
+}
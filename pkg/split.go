@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitRest 拆分后存放顶层非表key的文档名
+const SplitRest = "_root"
+
+// SplitTables 将顶层的每个表拆分为同名的文档，其余的顶层key放在SplitRest文档中，返回文档名到内容的映射
+func SplitTables(data map[string]any) (map[string]map[string]any, error) {
+	docs := map[string]map[string]any{}
+	for k, v := range data {
+		table, ok := v.(map[string]any)
+		if !ok {
+			if docs[SplitRest] == nil {
+				docs[SplitRest] = map[string]any{}
+			}
+			docs[SplitRest][k] = v
+			continue
+		}
+		if !validDocName(k) {
+			return nil, fmt.Errorf("table %q cannot be used as a file name", k)
+		}
+		docs[k] = table
+	}
+	return docs, nil
+}
+
+// JoinTables 是SplitTables的逆操作，SplitRest中的key放在顶层，其余每个文档作为同名的顶层表
+func JoinTables(docs map[string]map[string]any) (map[string]any, error) {
+	data := map[string]any{}
+	for k, v := range docs[SplitRest] {
+		data[k] = v
+	}
+	for name, doc := range docs {
+		if name == SplitRest {
+			continue
+		}
+		if _, exist := data[name]; exist {
+			return nil, fmt.Errorf("key %q is defined in both %s and %s", name, SplitRest, name)
+		}
+		data[name] = doc
+	}
+	return data, nil
+}
+
+// validDocName 表名可以直接作为文件名使用
+func validDocName(name string) bool {
+	switch name {
+	case "", ".", "..", SplitRest:
+		return false
+	}
+	return !strings.ContainsAny(name, "/\\\x00")
+}
@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSplitJoinRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		wantDocs []string
+	}{
+		{"tables only", "[server]\nport = 80\n[db]\nurl = \"x\"", []string{"db", "server"}},
+		{"root keys", "title = \"x\"\n[server]\nport = 80", []string{SplitRest, "server"}},
+		{"arrays stay at root", "[[items]]\nname = \"a\"\n[owner]\nname = \"b\"", []string{SplitRest, "owner"}},
+		{"nested tables", "[a.b.c]\nd = 1", []string{"a"}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := DecodeToml(strings.NewReader(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			docs, err := SplitTables(data)
+			if err != nil {
+				t.Fatalf("SplitTables error: %v", err)
+			}
+			var names []string
+			for name := range docs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.wantDocs) {
+				t.Fatalf("documents = %v, want %v", names, tt.wantDocs)
+			}
+			joined, err := JoinTables(docs)
+			if err != nil {
+				t.Fatalf("JoinTables error: %v", err)
+			}
+			if !reflect.DeepEqual(joined, data) {
+				t.Fatalf("JoinTables(SplitTables(x)) = %v, want %v", joined, data)
+			}
+		})
+	}
+}
+
+func TestSplitTablesErrors(t *testing.T) {
+	for _, name := range []string{"a/b", "..", SplitRest} {
+		data := map[string]any{name: map[string]any{"k": int64(1)}}
+		if _, err := SplitTables(data); err == nil {
+			t.Errorf("SplitTables with table %q succeeded, want an error", name)
+		}
+	}
+}
+
+func TestJoinTablesConflict(t *testing.T) {
+	docs := map[string]map[string]any{
+		SplitRest: {"server": int64(1)},
+		"server":  {"port": int64(80)},
+	}
+	if _, err := JoinTables(docs); err == nil {
+		t.Fatal("JoinTables succeeded, want a conflict error")
+	}
+}
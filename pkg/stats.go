@@ -0,0 +1,54 @@
+package pkg
+
+import "sort"
+
+// Stats 文档的统计信息
+type Stats struct {
+	Tables        int            // 表的数量，包括数组表的元素，不包括根表
+	Keys          int            // 键值对的数量
+	Arrays        int            // 数组的数量，包括数组表
+	MaxDepth      int            // 最大嵌套深度
+	Types         map[string]int // 各类型值的数量
+	LargestArrays []ArrayStat    // 按长度降序排列的数组
+	StringBytes   int            // 所有字符串值的字节数
+}
+
+// ArrayStat 数组的路径和长度
+type ArrayStat struct {
+	Path   []PathKey
+	Length int
+}
+
+// CollectStats 统计文档，top限制LargestArrays的数量，0表示不限制
+func CollectStats(data map[string]any, top int) *Stats {
+	stats := &Stats{Types: map[string]int{}}
+	Walk(data, func(path []PathKey, value any) bool {
+		if len(path) > stats.MaxDepth {
+			stats.MaxDepth = len(path)
+		}
+		if !path[len(path)-1].IsIndex {
+			stats.Keys++
+		}
+		stats.Types[TypeName(value)]++
+		switch v := value.(type) {
+		case map[string]any:
+			stats.Tables++
+		case string:
+			stats.StringBytes += len(v)
+		default:
+			if arr, ok := toArray(value); ok {
+				stats.Arrays++
+				stats.LargestArrays = append(stats.LargestArrays, ArrayStat{Path: path, Length: len(arr)})
+			}
+		}
+		return true
+	})
+
+	sort.SliceStable(stats.LargestArrays, func(i, j int) bool {
+		return stats.LargestArrays[i].Length > stats.LargestArrays[j].Length
+	})
+	if top > 0 && len(stats.LargestArrays) > top {
+		stats.LargestArrays = stats.LargestArrays[:top]
+	}
+	return stats
+}
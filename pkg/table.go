@@ -0,0 +1,191 @@
+package pkg
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// 表格形式的输出格式，适用于表的数组
+const (
+	FormatTable    = "table"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+)
+
+// IsTabularFormat 判断是否为表格形式的输出格式
+func IsTabularFormat(format string) bool {
+	return format == FormatTable || format == FormatMarkdown || format == FormatCSV
+}
+
+// TableRows 将值转换为表头和行：表的数组每个元素一行，列为所有key的并集；
+// 单个表输出为一行；标量的数组输出为名为value的一列
+func TableRows(value any) ([]string, [][]string, error) {
+	var items []any
+	if table, ok := value.(map[string]any); ok {
+		items = []any{table}
+	} else if arr, ok := toArray(value); ok {
+		items = arr
+	} else {
+		return nil, nil, fmt.Errorf("cannot render %s value as a table, want an array of tables", TypeName(value))
+	}
+
+	var header []string
+	seen := map[string]bool{}
+	tables := true
+	for _, item := range items {
+		table, ok := item.(map[string]any)
+		if !ok {
+			tables = false
+			break
+		}
+		for k := range table {
+			if !seen[k] {
+				seen[k] = true
+				header = append(header, k)
+			}
+		}
+	}
+
+	if !tables {
+		rows := make([][]string, len(items))
+		for i, item := range items {
+			rows[i] = []string{cellText(item)}
+		}
+		return []string{"value"}, rows, nil
+	}
+
+	sort.Strings(header)
+	rows := make([][]string, len(items))
+	for i, item := range items {
+		table := item.(map[string]any)
+		row := make([]string, len(header))
+		for j, k := range header {
+			if v, ok := table[k]; ok {
+				row[j] = cellText(v)
+			}
+		}
+		rows[i] = row
+	}
+	return header, rows, nil
+}
+
+// cellText 单元格中的文本，标量与FormatValue一致，表和数组输出为单行json
+func cellText(value any) string {
+	if IsScalar(value) {
+		text, _ := FormatValue(value)
+		return text
+	}
+	raw, err := json.Marshal(NormalizeValue(value))
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(raw)
+}
+
+// RenderTabular 按table、markdown或csv格式渲染表的数组
+func RenderTabular(value any, format string) (string, error) {
+	header, rows, err := TableRows(value)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case FormatTable:
+		return renderAligned(header, rows), nil
+	case FormatMarkdown:
+		return renderMarkdown(header, rows), nil
+	case FormatCSV:
+		return RenderCSV(header, rows, ',')
+	}
+	return "", fmt.Errorf("unknown table format %q, want one of table|markdown|csv", format)
+}
+
+// RenderCSV 以comma为分隔符输出带表头的csv
+func RenderCSV(header []string, rows [][]string, comma rune) (string, error) {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Comma = comma
+	w.Write(header)
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// DottedRows 与TableRows相同，但元素中嵌套的表展开为点分隔的列，如 dimensions.width
+func DottedRows(value any) ([]string, [][]string, error) {
+	arr, ok := toArray(value)
+	if !ok {
+		return TableRows(value)
+	}
+	items := make([]any, len(arr))
+	for i, item := range arr {
+		table, ok := item.(map[string]any)
+		if !ok {
+			items[i] = item
+			continue
+		}
+		flat := map[string]any{}
+		dottedColumns(flat, nil, table)
+		items[i] = flat
+	}
+	return TableRows(items)
+}
+
+func dottedColumns(flat map[string]any, path []PathKey, table map[string]any) {
+	for k, v := range table {
+		p := append(append([]PathKey(nil), path...), PathKey{Key: k})
+		if sub, ok := v.(map[string]any); ok && len(sub) > 0 {
+			dottedColumns(flat, p, sub)
+			continue
+		}
+		flat[FormatPath(p)] = v
+	}
+}
+
+// renderAligned 按显示宽度对齐各列，中文等宽字符也能对齐
+func renderAligned(header []string, rows [][]string) string {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], runewidth.StringWidth(cell))
+		}
+	}
+	lines := make([]string, 0, len(rows)+1)
+	for _, row := range append([][]string{header}, rows...) {
+		var sb strings.Builder
+		for i, cell := range row {
+			if i < len(row)-1 {
+				cell = runewidth.FillRight(cell, widths[i]+2)
+			}
+			sb.WriteString(cell)
+		}
+		lines = append(lines, strings.TrimRight(sb.String(), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderMarkdown(header []string, rows [][]string) string {
+	escape := strings.NewReplacer("|", `\|`, "\n", "<br>")
+	line := func(cells []string) string {
+		escaped := make([]string, len(cells))
+		for i, cell := range cells {
+			escaped[i] = escape.Replace(cell)
+		}
+		return "| " + strings.Join(escaped, " | ") + " |"
+	}
+	sep := make([]string, len(header))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	lines := []string{line(header), line(sep)}
+	for _, row := range rows {
+		lines = append(lines, line(row))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,201 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// TemplateFuncs 模板中可用的辅助函数，参数顺序与sprig一致，管道传入的值放在最后
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// 字符串
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      titleCase,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     repeatText,
+		"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       joinValues,
+		"quote":      func(v any) string { return fmt.Sprintf("%q", toText(v)) },
+		"squote":     func(v any) string { return "'" + toText(v) + "'" },
+		"indent":     indentText,
+		"nindent":    nindentText,
+		"toString":   toText,
+
+		// 默认值与条件
+		"default":  defaultValue,
+		"empty":    isEmpty,
+		"ternary":  ternaryValue,
+		"required": requiredValue,
+		"env":      os.Getenv,
+
+		// 表和数组
+		"list":   func(items ...any) []any { return items },
+		"dict":   dictValue,
+		"keys":   func(table map[string]any) []string { return SortedKeys(table) },
+		"hasKey": func(table map[string]any, key string) bool { _, ok := table[key]; return ok },
+		"get":    func(table map[string]any, key string) any { return table[key] },
+
+		// 数值
+		"add": func(a, b int64) int64 { return a + b },
+		"sub": func(a, b int64) int64 { return a - b },
+		"mul": func(a, b int64) int64 { return a * b },
+
+		// 序列化
+		"toJson":       func(v any) (string, error) { return RenderValueWith(v, FormatJSON, RenderOptions{Compact: true}) },
+		"toPrettyJson": func(v any) (string, error) { return RenderValue(v, FormatJSON) },
+		"toYaml":       func(v any) (string, error) { return RenderValue(v, FormatYAML) },
+		"toToml":       func(v any) (string, error) { return RenderValue(v, FormatTOML) },
+	}
+}
+
+func titleCase(s string) string {
+	upper := true
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			upper = true
+			return r
+		}
+		if upper {
+			upper = false
+			return unicode.ToUpper(r)
+		}
+		return r
+	}, s)
+}
+
+// toText 将模板中的值转换为文本，标量与FormatValue一致
+func toText(v any) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if IsScalar(v) {
+		text, _ := FormatValue(v)
+		return text
+	}
+	raw, err := json.Marshal(NormalizeValue(v))
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
+
+func joinValues(sep string, list any) string {
+	arr, ok := toArray(list)
+	if !ok {
+		if strs, isStrings := list.([]string); isStrings {
+			return strings.Join(strs, sep)
+		}
+		return toText(list)
+	}
+	parts := make([]string, len(arr))
+	for i, item := range arr {
+		parts[i] = toText(item)
+	}
+	return strings.Join(parts, sep)
+}
+
+func repeatText(n any, s string) (string, error) {
+	count, err := toCount(n)
+	if err != nil {
+		return "", err
+	}
+	return strings.Repeat(s, count), nil
+}
+
+func indentText(n any, s string) (string, error) {
+	count, err := toCount(n)
+	if err != nil {
+		return "", err
+	}
+	pad := strings.Repeat(" ", count)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad), nil
+}
+
+func nindentText(n any, s string) (string, error) {
+	out, err := indentText(n, s)
+	return "\n" + out, err
+}
+
+// toCount 将模板中的次数转换为int，数据文件中的整数为int64，json中的整数也可能是没有小数部分的float64
+func toCount(v any) (int, error) {
+	var n int64
+	switch c := v.(type) {
+	case int:
+		n = int64(c)
+	case int64:
+		n = c
+	case float64:
+		if c != float64(int64(c)) {
+			return 0, fmt.Errorf("count must be an integer, got %v", c)
+		}
+		n = int64(c)
+	default:
+		return 0, fmt.Errorf("count must be an integer, got %s", TypeName(v))
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("count must not be negative, got %d", n)
+	}
+	return int(n), nil
+}
+
+// defaultValue 值为空时返回默认值，如 {{ .port | default 8080 }}
+func defaultValue(def any, value ...any) any {
+	if len(value) == 0 || isEmpty(value[0]) {
+		return def
+	}
+	return value[0]
+}
+
+// isEmpty 与sprig一致，零值、空字符串、空表和空数组都视为空
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+func ternaryValue(yes, no any, cond bool) any {
+	if cond {
+		return yes
+	}
+	return no
+}
+
+func requiredValue(msg string, value any) (any, error) {
+	if isEmpty(value) {
+		return nil, errors.New(msg)
+	}
+	return value, nil
+}
+
+func dictValue(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict expects key value pairs")
+	}
+	table := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		table[toText(pairs[i])] = pairs[i+1]
+	}
+	return table, nil
+}
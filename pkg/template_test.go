@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestTemplateCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		tmpl   string
+		want   string
+	}{
+		{"repeat toml integer", FormatTOML, "width = 3", `{{ repeat .width "-" }}`, "---"},
+		{"repeat json number", FormatJSON, `{"width": 2}`, `{{ repeat .width "=" }}`, "=="},
+		{"indent yaml integer", FormatYAML, "pad: 2", `{{ indent .pad "a\nb" }}`, "  a\n  b"},
+		{"nindent literal", FormatTOML, "", `{{ nindent 1 "x" }}`, "\n x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := DecodeData(strings.NewReader(tt.data), tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tmpl := template.Must(template.New("t").Funcs(TemplateFuncs()).Parse(tt.tmpl))
+			var sb strings.Builder
+			if err := tmpl.Execute(&sb, data); err != nil {
+				t.Fatal(err)
+			}
+			if sb.String() != tt.want {
+				t.Fatalf("got %q, want %q", sb.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateCountErrors(t *testing.T) {
+	for _, text := range []string{`{{ repeat 1.5 "-" }}`, `{{ repeat -1 "-" }}`, `{{ indent "2" "x" }}`} {
+		tmpl := template.Must(template.New("t").Funcs(TemplateFuncs()).Parse(text))
+		if err := tmpl.Execute(&strings.Builder{}, nil); err == nil || !strings.Contains(err.Error(), "count must") {
+			t.Fatalf("%s: error = %v, want a count error", text, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// SyntaxError toml、json或yaml的语法错误及其位置
+type SyntaxError struct {
+	Line    int    // 行号，从1开始
+	Col     int    // 列号，从1开始，yaml的错误没有列号时为0
+	Len     int    // 出错部分的字节数
+	Message string // 简短的错误描述
+	Usage   string // 更详细的用法说明，可能为空
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Col == 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Col, e.Message)
+}
+
+// AsSyntaxError 如果err是语法错误，返回其位置信息
+func AsSyntaxError(err error) (*SyntaxError, bool) {
+	var se *SyntaxError
+	if errors.As(err, &se) {
+		return se, true
+	}
+	var pe toml.ParseError
+	if !errors.As(err, &pe) {
+		return nil, false
+	}
+	return &SyntaxError{
+		Line:    pe.Position.Line,
+		Col:     pe.Position.Col,
+		Len:     pe.Position.Len,
+		Message: pe.Message,
+		Usage:   pe.Usage,
+	}, true
+}
+
+// SourceError 带有源文本的语法错误，输出时展示出错行附近的源码、指向出错列的标记和修改提示
+type SourceError struct {
+	Name string // 文件名
+	Src  []byte
+	Err  error
+}
+
+// snippetContext 出错行前后展示的行数
+const snippetContext = 2
+
+func (e *SourceError) Error() string {
+	se, ok := AsSyntaxError(e.Err)
+	if !ok {
+		return fmt.Sprintf("%s: %s", e.Name, e.Err)
+	}
+
+	var sb strings.Builder
+	if se.Col == 0 {
+		fmt.Fprintf(&sb, "%s:%d: %s\n", e.Name, se.Line, se.Message)
+	} else {
+		fmt.Fprintf(&sb, "%s:%d:%d: %s\n", e.Name, se.Line, se.Col, se.Message)
+	}
+	lines := strings.Split(strings.TrimRight(string(e.Src), "\n"), "\n")
+	for len(lines) < se.Line {
+		// 文件末尾的错误指向最后一个换行之后
+		lines = append(lines, "")
+	}
+	first := max(se.Line-snippetContext, 1)
+	last := min(se.Line+snippetContext, len(lines))
+	width := len(fmt.Sprint(last))
+	for n := first; n <= last; n++ {
+		line := strings.TrimRight(lines[n-1], "\r")
+		fmt.Fprintf(&sb, "%*d | %s\n", width, n, line)
+		if n == se.Line && se.Col > 0 {
+			fmt.Fprintf(&sb, "%*s | %s\n", width, "", caret(line, se.Col, se.Len))
+		}
+	}
+	if hint := SyntaxHint(se.Message); len(hint) > 0 {
+		fmt.Fprintf(&sb, "hint: %s\n", hint)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// caret 生成指向出错列的标记行，保留行首的tab使标记与源码对齐
+func caret(line string, col, length int) string {
+	var pad strings.Builder
+	for i, r := range line {
+		if i >= col-1 {
+			break
+		}
+		if r == '\t' {
+			pad.WriteRune('\t')
+		} else {
+			pad.WriteRune(' ')
+		}
+	}
+	return pad.String() + strings.Repeat("^", max(length, 1))
+}
+
+// syntaxHints 常见错误信息片段对应的修改提示
+var syntaxHints = []struct {
+	match string
+	hint  string
+}{
+	{`expected value but found '\n'`, "every key needs a value after '='"},
+	{"expected value but found", `strings must be quoted, e.g. name = "value"`},
+	{"expected value", "every key needs a value after '='"},
+	{"key name appears blank", "a key is missing before '='"},
+	{"has already been defined", "each key can only be defined once per table"},
+	{"already created", "a key and a table with the same name cannot both be defined"},
+	{"newlines not allowed within inline tables", "inline tables must fit on one line, use a [table] header instead"},
+	{"trailing comma not allowed", "remove the comma before '}'"},
+	{"inline table terminator", "separate inline table entries with commas and close it with '}'"},
+	{"array terminator", "separate array elements with commas and close the array with ']'"},
+	{`expected '"'`, "a string is missing its closing quote"},
+	{"strings cannot contain newlines", `a string is missing its closing quote, use """ for multi-line strings`},
+	{`expected "'"`, "a string is missing its closing quote"},
+	{"expected key separator '='", "keys and values are separated by '='"},
+	{"to end with a newline", "put each key = value pair on its own line"},
+	{"table name", "table headers look like [name] or [[name]]"},
+	{"invalid datetime", "datetimes look like 1979-05-27T07:32:00Z, 1979-05-27 or 07:32:00"},
+}
+
+// SyntaxHint 根据错误信息给出修改提示，没有合适的提示时返回空字符串
+func SyntaxHint(message string) string {
+	for _, h := range syntaxHints {
+		if strings.Contains(message, h.match) {
+			return h.hint
+		}
+	}
+	return ""
+}
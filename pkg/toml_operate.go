@@ -0,0 +1,145 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// DecodeTomlFile 读取toml文件，gzip、zstd和bzip2压缩的文件会自动解压（解压后不超过maxSize字节，0表示不限制），
+// 语法错误时返回带有源码片段的SourceError
+func DecodeTomlFile(filePath string, maxSize int64) (map[string]any, error) {
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if src, err = Decompress(src, maxSize); err != nil {
+		return nil, err
+	}
+	return DecodeTomlSource(filePath, src)
+}
+
+// DecodeTomlSource 解码toml源文本，name用于错误信息中的文件名
+func DecodeTomlSource(name string, src []byte) (map[string]any, error) {
+	data, err := DecodeToml(bytes.NewReader(src))
+	if _, ok := AsSyntaxError(err); ok {
+		return nil, &SourceError{Name: name, Src: src, Err: err}
+	}
+	return data, err
+}
+
+// DecodeTomlSourceOrdered 与DecodeTomlSource相同，同时返回key在文档中出现的顺序
+func DecodeTomlSourceOrdered(name string, src []byte) (map[string]any, KeyOrder, error) {
+	data, order, err := DecodeTomlOrdered(bytes.NewReader(src))
+	if _, ok := AsSyntaxError(err); ok {
+		return nil, nil, &SourceError{Name: name, Src: src, Err: err}
+	}
+	return data, order, err
+}
+
+// DecodeToml 从reader中流式解码toml
+func DecodeToml(r io.Reader) (map[string]any, error) {
+	data := make(map[string]any)
+	if _, err := toml.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// FindValue 按照路径表达式查找值，如 server.port、servers[1].host
+func FindValue(data map[string]any, path string) (any, bool, error) {
+	keys, err := ParsePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	value, ok := Lookup(data, keys)
+	return value, ok, nil
+}
+
+// FormatValue 标量输出为文本（nan和inf写作 nan、inf、-inf），table和array输出为格式化的json
+func FormatValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64, bool:
+		return fmt.Sprint(v), nil
+	case float64:
+		// nan和inf按toml的写法输出
+		return fmt.Sprint(NormalizeValue(v)), nil
+	case time.Time:
+		return formatTime(v), nil
+	}
+	raw, err := json.MarshalIndent(NormalizeValue(value), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// NormalizeValue 将时间类型转换为toml中的文本形式，避免json输出时丢失本地时间语义
+func NormalizeValue(value any) any {
+	return normalizeValue(value, DatetimeString)
+}
+
+func normalizeValue(value any, datetime string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = normalizeValue(item, datetime)
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeValue(item, datetime)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeValue(item, datetime)
+		}
+		return out
+	case time.Time:
+		switch datetime {
+		case DatetimeUnix:
+			if TypeName(v) == "datetime" {
+				return v.Unix()
+			}
+		case DatetimeTagged:
+			return map[string]any{"type": TypeName(v), "value": formatTime(v)}
+		}
+		return formatTime(v)
+	case float64:
+		// json不支持nan和inf，按toml的写法输出为字符串
+		switch {
+		case math.IsNaN(v):
+			return "nan"
+		case math.IsInf(v, 1):
+			return "inf"
+		case math.IsInf(v, -1):
+			return "-inf"
+		}
+	}
+	return value
+}
+
+// formatTime 按照toml的四种时间类型输出对应格式
+func formatTime(t time.Time) string {
+	switch t.Location().String() {
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	case "date-local":
+		return t.Format("2006-01-02")
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	}
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TreeOptions 树形输出的设置
+type TreeOptions struct {
+	Depth  int  // 最大深度，0表示不限制
+	Box    bool // 使用制表符画线，否则只缩进
+	Values bool // 标量同时输出值
+}
+
+// Tree 将文档输出为树形结构，表和数组显示子节点数量，标量显示类型
+func Tree(data map[string]any, opts TreeOptions) string {
+	var sb strings.Builder
+	sb.WriteString(".")
+	t := &treeWriter{sb: &sb, opts: opts}
+	t.children(data, "", 1)
+	return sb.String()
+}
+
+type treeWriter struct {
+	sb   *strings.Builder
+	opts TreeOptions
+}
+
+type treeChild struct {
+	label string
+	value any
+}
+
+func (t *treeWriter) children(value any, prefix string, depth int) {
+	var children []treeChild
+	if table, ok := value.(map[string]any); ok {
+		for _, k := range SortedKeys(table) {
+			children = append(children, treeChild{label: FormatPath([]PathKey{{Key: k}}), value: table[k]})
+		}
+	} else if arr, ok := toArray(value); ok {
+		for i, item := range arr {
+			children = append(children, treeChild{label: fmt.Sprintf("[%d]", i), value: item})
+		}
+	}
+
+	for i, child := range children {
+		last := i == len(children)-1
+		branch, indent := "  ", "  "
+		if t.opts.Box {
+			branch, indent = "├── ", "│   "
+			if last {
+				branch, indent = "└── ", "    "
+			}
+		}
+		t.sb.WriteString("\n" + prefix + branch + child.label + t.describe(child.value))
+		if IsScalar(child.value) {
+			continue
+		}
+		if t.opts.Depth > 0 && depth >= t.opts.Depth {
+			continue
+		}
+		t.children(child.value, prefix+indent, depth+1)
+	}
+}
+
+// describe 节点标签后的说明，如 (table, 3 keys)、(array, 2 items)、: string
+func (t *treeWriter) describe(value any) string {
+	if table, ok := value.(map[string]any); ok {
+		return fmt.Sprintf(" (table, %s)", plural(len(table), "key"))
+	}
+	if arr, ok := toArray(value); ok {
+		return fmt.Sprintf(" (array, %s)", plural(len(arr), "item"))
+	}
+	out := ": " + TypeName(value)
+	if t.opts.Values {
+		out += " = " + InlineValue(value)
+	}
+	return out
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"sort"
+	"time"
+)
+
+// WalkFunc 遍历时对每个节点调用，返回false时不再进入该节点的子节点
+type WalkFunc func(path []PathKey, value any) bool
+
+// Walk 深度优先遍历文档中的所有节点（不包括根节点），表中的key按字典序访问
+func Walk(data map[string]any, fn WalkFunc) {
+	walkTable(nil, data, fn)
+}
+
+func walkValue(path []PathKey, value any, fn WalkFunc) {
+	if !fn(path, value) {
+		return
+	}
+	if table, ok := value.(map[string]any); ok {
+		walkTable(path, table, fn)
+		return
+	}
+	if arr, ok := toArray(value); ok {
+		for i, item := range arr {
+			walkValue(appendPath(path, PathKey{Index: i, IsIndex: true}), item, fn)
+		}
+	}
+}
+
+func walkTable(path []PathKey, table map[string]any, fn WalkFunc) {
+	for _, k := range SortedKeys(table) {
+		walkValue(appendPath(path, PathKey{Key: k}), table[k], fn)
+	}
+}
+
+// SortedKeys 返回表中按字典序排列的key
+func SortedKeys(table map[string]any) []string {
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsScalar 判断值是否为标量，即不是table也不是array
+func IsScalar(value any) bool {
+	switch value.(type) {
+	case map[string]any, []any, []map[string]any:
+		return false
+	}
+	return true
+}
+
+// TypeName 返回值对应的toml类型名称
+func TypeName(value any) string {
+	switch v := value.(type) {
+	case nil:
+		// 只会出现在json和yaml中
+		return "null"
+	case map[string]any:
+		return "table"
+	case []any, []map[string]any:
+		return "array"
+	case string:
+		return "string"
+	case int64:
+		return "integer"
+	case float64:
+		return "float"
+	case bool:
+		return "boolean"
+	case time.Time:
+		switch v.Location().String() {
+		case "datetime-local":
+			return "local-datetime"
+		case "date-local":
+			return "local-date"
+		case "time-local":
+			return "local-time"
+		}
+		return "datetime"
+	}
+	return "unknown"
+}
@@ -0,0 +1,182 @@
+package pkg
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreFiles 遍历目录时读取的忽略规则文件，语法与.gitignore相同
+var ignoreFiles = []string{".gitignore", ".aqignore"}
+
+// ignoreRule 一条忽略规则
+type ignoreRule struct {
+	base    string // 规则文件所在的目录
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+	hasDir  bool // 规则中包含'/'时相对base匹配，否则匹配任意层级的文件名
+}
+
+// WalkFiles 递归遍历目录，返回文件名匹配include且不匹配exclude、也没有被.gitignore/.aqignore忽略的文件
+func WalkFiles(root string, include, exclude []string) ([]string, error) {
+	var files []string
+	rulesByDir := map[string][]ignoreRule{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root {
+			rules := rulesByDir[filepath.Dir(path)]
+			if d.IsDir() && d.Name() == ".git" || isIgnored(rules, path, d.IsDir()) || matchAny(exclude, root, path) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		if d.IsDir() {
+			// 子目录继承父目录的规则，再追加自己的规则文件
+			rules := append([]ignoreRule(nil), rulesByDir[filepath.Dir(path)]...)
+			for _, name := range ignoreFiles {
+				loaded, err := loadIgnoreRules(filepath.Join(path, name), path)
+				if err != nil {
+					return err
+				}
+				rules = append(rules, loaded...)
+			}
+			rulesByDir[path] = rules
+			return nil
+		}
+
+		if len(include) == 0 || matchAny(include, root, path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// matchAny 模式中包含'/'时匹配相对root的路径，否则匹配文件名
+func matchAny(patterns []string, root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+	for _, p := range patterns {
+		target := filepath.Base(path)
+		if strings.Contains(p, "/") {
+			target = rel
+		}
+		if globRegexp(p).MatchString(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIgnored 按顺序应用规则，最后一条匹配的规则生效
+func isIgnored(rules []ignoreRule, path string, isDir bool) bool {
+	ignored := false
+	for _, r := range rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		target := filepath.Base(path)
+		if r.hasDir {
+			rel, err := filepath.Rel(r.base, path)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			target = filepath.ToSlash(rel)
+		}
+		if r.re.MatchString(target) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// loadIgnoreRules 读取规则文件，文件不存在时返回空
+func loadIgnoreRules(file, base string) ([]ignoreRule, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.hasDir = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		rule.re = globRegexp(line)
+		rules = append(rules, rule)
+	}
+	return rules, scanner.Err()
+}
+
+// globRegexp 将glob转换为正则，支持 * ? [...] 和 **
+func globRegexp(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return regexp.MustCompile(regexp.QuoteMeta(pattern))
+	}
+	return re
+}